@@ -0,0 +1,74 @@
+package trie
+
+import (
+	"unicode/utf8"
+
+	"github.com/caravan/go-immutable-trie/key"
+)
+
+type (
+	// automaton recognizes Keys one unit of input at a time, so that a
+	// Trie can be walked with it. A subtree is pruned as soon as the path
+	// its Keys share leads the automaton to a state that can't accept
+	automaton[State any] interface {
+		start() State
+		step(State, rune) State
+		viable(State) bool
+		accepts(State) bool
+
+		// decode returns the next unit of input and its size in bytes, or
+		// false if the bytes end partway through it
+		decode([]byte) (rune, int, bool)
+	}
+
+	// position is the State of an automaton after reading the leading
+	// bytes of a Key, up to offset
+	position[State any] struct {
+		state  State
+		offset int
+	}
+)
+
+// enter advances an automaton through the bytes that every Key in this
+// node's subtree shares, returning the depth of the node's children. It
+// reports false if no Key in the subtree can be accepted
+func enter[Key key.Keyable, Value any, State any](
+	t *trie[Key, Value], a automaton[State], p position[State], depth int,
+) (position[State], int, bool) {
+	depth += t.skip()
+	p, ok := read(a, p, []byte(t.key), depth*t.cfg.strategy().Bits()/8)
+	return p, depth + 1, ok
+}
+
+// read advances an automaton through the bytes of a Key up to limit,
+// stopping short of a unit that crosses it
+func read[State any](
+	a automaton[State], p position[State], k []byte, limit int,
+) (position[State], bool) {
+	for p.offset < limit {
+		r, size, ok := a.decode(k[p.offset:limit])
+		if !ok {
+			break
+		}
+		p = position[State]{a.step(p.state, r), p.offset + size}
+		if !a.viable(p.state) {
+			return p, false
+		}
+	}
+	return p, true
+}
+
+// acceptsKey reports whether an automaton accepts an entire Key. If the
+// Key ends partway through a rune, each of its remaining bytes is read as
+// utf8.RuneError
+func acceptsKey[State any](
+	a automaton[State], p position[State], k []byte,
+) bool {
+	p, ok := read(a, p, k, len(k))
+	for ok && p.offset < len(k) {
+		_, size := utf8.DecodeRune(k[p.offset:])
+		p = position[State]{a.step(p.state, utf8.RuneError), p.offset + size}
+		ok = a.viable(p.state)
+	}
+	return ok && a.accepts(p.state)
+}
@@ -0,0 +1,216 @@
+package bench_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/caravan/go-immutable-trie/bench"
+	"github.com/caravan/go-immutable-trie/key"
+)
+
+type benchFunc[Key key.Keyable] func(*testing.B, bench.Factory[Key], []Key)
+
+var (
+	sizes = []int{1_000, 10_000, 100_000}
+
+	distributions = map[string]func(int) []string{
+		"random": func(n int) []string {
+			rng := rand.New(rand.NewSource(44))
+			seen := map[string]bool{}
+			res := make([]string, 0, n)
+			for len(res) < n {
+				k := fmt.Sprintf("%016x", rng.Uint64())
+				if !seen[k] {
+					seen[k] = true
+					res = append(res, k)
+				}
+			}
+			return res
+		},
+		"shared-prefix": func(n int) []string {
+			res := make([]string, n)
+			for i, j := range rand.New(rand.NewSource(44)).Perm(n) {
+				res[i] = fmt.Sprintf("/api/v1/tenants/%08d/config", j)
+			}
+			return res
+		},
+		"sequential": func(n int) []string {
+			res := make([]string, n)
+			for i := range res {
+				res[i] = fmt.Sprintf("%010d", i)
+			}
+			return res
+		},
+	}
+)
+
+func BenchmarkGet(b *testing.B) {
+	runCases(b, benchGet[string], benchGet[[]byte])
+}
+
+func BenchmarkPut(b *testing.B) {
+	runCases(b, benchPut[string], benchPut[[]byte])
+}
+
+func BenchmarkRemove(b *testing.B) {
+	runCases(b, benchRemove[string], benchRemove[[]byte])
+}
+
+func BenchmarkRemovePrefix(b *testing.B) {
+	runCases(b, benchRemovePrefix[string], benchRemovePrefix[[]byte])
+}
+
+func BenchmarkScan(b *testing.B) {
+	runCases(b, benchScan[string], benchScan[[]byte])
+}
+
+func BenchmarkFrom(b *testing.B) {
+	runCases(b, benchFrom[string], benchFrom[[]byte])
+}
+
+func runCases(b *testing.B, str benchFunc[string], bytes benchFunc[[]byte]) {
+	for _, dist := range sortedNames(distributions) {
+		for _, size := range sizes {
+			keys := distributions[dist](size)
+			name := fmt.Sprintf("%s/%d", dist, size)
+			b.Run("string/"+name, func(b *testing.B) {
+				runStores(b, keys, str)
+			})
+			b.Run("bytes/"+name, func(b *testing.B) {
+				runStores(b, toBytes(keys), bytes)
+			})
+		}
+	}
+}
+
+func runStores[Key key.Keyable](b *testing.B, keys []Key, fn benchFunc[Key]) {
+	factories := bench.Factories[Key]()
+	for _, name := range sortedNames(factories) {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			fn(b, factories[name], keys)
+		})
+	}
+}
+
+func benchGet[Key key.Keyable](
+	b *testing.B, factory bench.Factory[Key], keys []Key,
+) {
+	s := build(factory, keys)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Get(keys[i%len(keys)])
+	}
+}
+
+func benchPut[Key key.Keyable](
+	b *testing.B, factory bench.Factory[Key], keys []Key,
+) {
+	var s bench.Store[Key]
+	for i := 0; i < b.N; i++ {
+		if i%len(keys) == 0 {
+			s = factory()
+		}
+		s = s.Put(keys[i%len(keys)], i)
+	}
+}
+
+func benchRemove[Key key.Keyable](
+	b *testing.B, factory bench.Factory[Key], keys []Key,
+) {
+	var s bench.Store[Key]
+	for i := 0; i < b.N; i++ {
+		if i%len(keys) == 0 {
+			b.StopTimer()
+			s = build(factory, keys)
+			b.StartTimer()
+		}
+		s = s.Remove(keys[i%len(keys)])
+	}
+}
+
+func benchRemovePrefix[Key key.Keyable](
+	b *testing.B, factory bench.Factory[Key], keys []Key,
+) {
+	prefix := prefixOfPercent(keys)
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		s := build(factory, keys)
+		b.StartTimer()
+		s.RemovePrefix(prefix)
+	}
+}
+
+func benchScan[Key key.Keyable](
+	b *testing.B, factory bench.Factory[Key], keys []Key,
+) {
+	s := build(factory, keys)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Scan()
+	}
+}
+
+func benchFrom[Key key.Keyable](
+	b *testing.B, factory bench.Factory[Key], keys []Key,
+) {
+	s := build(factory, keys)
+	if !s.Ordered() {
+		b.Skip("store is unordered")
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.From(keys[i%len(keys)], 10)
+	}
+}
+
+func build[Key key.Keyable](factory bench.Factory[Key], keys []Key) bench.Store[Key] {
+	s := factory()
+	for i, k := range keys {
+		s = s.Put(k, i)
+	}
+	return s
+}
+
+// prefixOfPercent returns the longest prefix of a Key near the middle of
+// the sorted Keys that is shared by at least one percent of them
+func prefixOfPercent[Key key.Keyable](keys []Key) Key {
+	sorted := make([]string, len(keys))
+	for i, k := range keys {
+		sorted[i] = string(k)
+	}
+	sort.Strings(sorted)
+	mid := sorted[len(sorted)/2]
+	for size := len(mid); size > 0; size-- {
+		count := 0
+		for _, k := range sorted {
+			if strings.HasPrefix(k, mid[:size]) {
+				count++
+			}
+		}
+		if count >= len(keys)/100 {
+			return Key(mid[:size])
+		}
+	}
+	return Key("")
+}
+
+func toBytes(keys []string) [][]byte {
+	res := make([][]byte, len(keys))
+	for i, k := range keys {
+		res[i] = []byte(k)
+	}
+	return res
+}
+
+func sortedNames[T any](m map[string]T) []string {
+	res := make([]string, 0, len(m))
+	for name := range m {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
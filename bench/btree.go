@@ -0,0 +1,141 @@
+package bench
+
+import (
+	"sort"
+
+	"github.com/caravan/go-immutable-trie/key"
+)
+
+type (
+	// btree is a two-level B+tree: a sorted sequence of leaves, each
+	// holding up to btreeDegree sorted entries. It stands in for a
+	// general B-tree, having the same logarithmic search and block-wise,
+	// cache-friendly scans
+	btree[Key key.Keyable] struct {
+		leaves []*btreeLeaf[Key]
+	}
+
+	btreeLeaf[Key key.Keyable] struct {
+		entries []entry[Key]
+	}
+)
+
+// btreeDegree is the maximum number of entries held by a leaf
+const btreeDegree = 64
+
+func (t *btree[Key]) Get(k Key) (int, bool) {
+	if l := t.leafFor(k); l >= 0 {
+		leaf := t.leaves[l]
+		if i, ok := searchEntries(leaf.entries, k); ok {
+			return leaf.entries[i].value, true
+		}
+	}
+	return 0, false
+}
+
+func (t *btree[Key]) Put(k Key, v int) Store[Key] {
+	l := max(t.leafFor(k), 0)
+	if len(t.leaves) == 0 {
+		t.leaves = []*btreeLeaf[Key]{{}}
+	}
+	leaf := t.leaves[l]
+	i, ok := searchEntries(leaf.entries, k)
+	if ok {
+		leaf.entries[i].value = v
+		return t
+	}
+	leaf.entries = append(leaf.entries, entry[Key]{})
+	copy(leaf.entries[i+1:], leaf.entries[i:])
+	leaf.entries[i] = entry[Key]{k, v}
+	if len(leaf.entries) > btreeDegree {
+		t.split(l)
+	}
+	return t
+}
+
+func (t *btree[Key]) Remove(k Key) Store[Key] {
+	if l := t.leafFor(k); l >= 0 {
+		leaf := t.leaves[l]
+		if i, ok := searchEntries(leaf.entries, k); ok {
+			leaf.entries = append(leaf.entries[:i], leaf.entries[i+1:]...)
+			t.prune(l)
+		}
+	}
+	return t
+}
+
+func (t *btree[Key]) RemovePrefix(k Key) Store[Key] {
+	l := max(t.leafFor(k), 0)
+	for l < len(t.leaves) {
+		leaf := t.leaves[l]
+		start, _ := searchEntries(leaf.entries, k)
+		end := start
+		for end < len(leaf.entries) &&
+			key.StartsWith(leaf.entries[end].key, k) {
+			end++
+		}
+		if start == end && start < len(leaf.entries) {
+			break
+		}
+		leaf.entries = append(leaf.entries[:start], leaf.entries[end:]...)
+		if !t.prune(l) {
+			l++
+		}
+	}
+	return t
+}
+
+func (t *btree[Key]) Scan() int {
+	res := 0
+	for _, leaf := range t.leaves {
+		for range leaf.entries {
+			res++
+		}
+	}
+	return res
+}
+
+func (t *btree[Key]) From(k Key, limit int) int {
+	res := 0
+	l := max(t.leafFor(k), 0)
+	if l < len(t.leaves) {
+		i, _ := searchEntries(t.leaves[l].entries, k)
+		for ; l < len(t.leaves) && res < limit; l, i = l+1, 0 {
+			res += min(limit-res, len(t.leaves[l].entries)-i)
+		}
+	}
+	return res
+}
+
+func (*btree[Key]) Ordered() bool {
+	return true
+}
+
+// leafFor returns the index of the last leaf whose least Key is less than
+// or equal to the provided Key, or -1 if there isn't one
+func (t *btree[Key]) leafFor(k Key) int {
+	return sort.Search(len(t.leaves), func(i int) bool {
+		return key.GreaterThan[Key](t.leaves[i].entries[0].key, k)
+	}) - 1
+}
+
+func (t *btree[Key]) split(l int) {
+	leaf := t.leaves[l]
+	half := len(leaf.entries) / 2
+	next := &btreeLeaf[Key]{
+		entries: append([]entry[Key]{}, leaf.entries[half:]...),
+	}
+	leaf.entries = leaf.entries[:half:half]
+	t.leaves = append(t.leaves, nil)
+	copy(t.leaves[l+2:], t.leaves[l+1:])
+	t.leaves[l+1] = next
+}
+
+// prune removes a leaf that has become empty, reporting whether it did
+func (t *btree[Key]) prune(l int) bool {
+	if len(t.leaves[l].entries) > 0 {
+		return false
+	}
+	t.leaves = append(t.leaves[:l], t.leaves[l+1:]...)
+	return true
+}
@@ -0,0 +1,211 @@
+// Package bench compares the Trie against other ordered and unordered
+// structures: Go's built-in map, a sorted slice and a B-tree-like
+// reference. Each is wrapped as a Store so that the benchmarks can drive
+// them identically
+package bench
+
+import (
+	"sort"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/caravan/go-immutable-trie/key"
+)
+
+type (
+	// Store is the common interface of the structures being compared.
+	// Writes return the resulting Store, which for mutable structures is
+	// the same instance
+	Store[Key key.Keyable] interface {
+		Get(Key) (int, bool)
+		Put(Key, int) Store[Key]
+		Remove(Key) Store[Key]
+		RemovePrefix(Key) Store[Key]
+
+		// Scan visits every entry, returning how many there were. Only
+		// ordered Stores visit them in Key order
+		Scan() int
+
+		// From visits up to the provided number of entries in Key order,
+		// starting at the least Key greater than or equal to the one
+		// provided, returning how many there were
+		From(Key, int) int
+
+		// Ordered reports whether the Store supports From
+		Ordered() bool
+	}
+
+	// Factory creates an empty Store
+	Factory[Key key.Keyable] func() Store[Key]
+
+	trieStore[Key key.Keyable] struct {
+		trie.Trie[Key, int]
+	}
+
+	mapStore[Key key.Keyable] map[string]int
+
+	sliceStore[Key key.Keyable] struct {
+		entries []entry[Key]
+	}
+
+	entry[Key key.Keyable] struct {
+		key   Key
+		value int
+	}
+)
+
+// Factories returns the Stores to be compared, by name
+func Factories[Key key.Keyable]() map[string]Factory[Key] {
+	return map[string]Factory[Key]{
+		"trie": func() Store[Key] {
+			return trieStore[Key]{trie.New[Key, int]()}
+		},
+		"trie-compressed": func() Store[Key] {
+			return trieStore[Key]{
+				trie.New[Key, int](trie.WithPathCompression()),
+			}
+		},
+		"map": func() Store[Key] {
+			return mapStore[Key]{}
+		},
+		"slice": func() Store[Key] {
+			return &sliceStore[Key]{}
+		},
+		"btree": func() Store[Key] {
+			return &btree[Key]{}
+		},
+	}
+}
+
+func (s trieStore[Key]) Put(k Key, v int) Store[Key] {
+	return trieStore[Key]{s.Trie.Put(k, v)}
+}
+
+func (s trieStore[Key]) Remove(k Key) Store[Key] {
+	_, res, _ := s.Trie.Remove(k)
+	return trieStore[Key]{res}
+}
+
+func (s trieStore[Key]) RemovePrefix(k Key) Store[Key] {
+	res, _ := s.Trie.RemovePrefix(k)
+	return trieStore[Key]{res}
+}
+
+func (s trieStore[Key]) Scan() int {
+	res := 0
+	s.Select().All().ForEach(func(Key, int) {
+		res++
+	})
+	return res
+}
+
+func (s trieStore[Key]) From(k Key, limit int) int {
+	return s.Select().From(k).Take(limit).Count()
+}
+
+func (trieStore[Key]) Ordered() bool {
+	return true
+}
+
+func (s mapStore[Key]) Get(k Key) (int, bool) {
+	res, ok := s[string(k)]
+	return res, ok
+}
+
+func (s mapStore[Key]) Put(k Key, v int) Store[Key] {
+	s[string(k)] = v
+	return s
+}
+
+func (s mapStore[Key]) Remove(k Key) Store[Key] {
+	delete(s, string(k))
+	return s
+}
+
+func (s mapStore[Key]) RemovePrefix(k Key) Store[Key] {
+	for e := range s {
+		if key.StartsWith(Key(e), k) {
+			delete(s, e)
+		}
+	}
+	return s
+}
+
+func (s mapStore[Key]) Scan() int {
+	res := 0
+	for range s {
+		res++
+	}
+	return res
+}
+
+func (mapStore[Key]) From(Key, int) int {
+	panic("programmer error: map stores are unordered")
+}
+
+func (mapStore[Key]) Ordered() bool {
+	return false
+}
+
+func (s *sliceStore[Key]) Get(k Key) (int, bool) {
+	if i, ok := s.search(k); ok {
+		return s.entries[i].value, true
+	}
+	return 0, false
+}
+
+func (s *sliceStore[Key]) Put(k Key, v int) Store[Key] {
+	i, ok := s.search(k)
+	if ok {
+		s.entries[i].value = v
+		return s
+	}
+	s.entries = append(s.entries, entry[Key]{})
+	copy(s.entries[i+1:], s.entries[i:])
+	s.entries[i] = entry[Key]{k, v}
+	return s
+}
+
+func (s *sliceStore[Key]) Remove(k Key) Store[Key] {
+	if i, ok := s.search(k); ok {
+		s.entries = append(s.entries[:i], s.entries[i+1:]...)
+	}
+	return s
+}
+
+func (s *sliceStore[Key]) RemovePrefix(k Key) Store[Key] {
+	start, _ := s.search(k)
+	end := start
+	for end < len(s.entries) && key.StartsWith(s.entries[end].key, k) {
+		end++
+	}
+	s.entries = append(s.entries[:start], s.entries[end:]...)
+	return s
+}
+
+func (s *sliceStore[Key]) Scan() int {
+	res := 0
+	for range s.entries {
+		res++
+	}
+	return res
+}
+
+func (s *sliceStore[Key]) From(k Key, limit int) int {
+	i, _ := s.search(k)
+	return min(limit, len(s.entries)-i)
+}
+
+func (*sliceStore[Key]) Ordered() bool {
+	return true
+}
+
+func (s *sliceStore[Key]) search(k Key) (int, bool) {
+	return searchEntries(s.entries, k)
+}
+
+func searchEntries[Key key.Keyable](entries []entry[Key], k Key) (int, bool) {
+	i := sort.Search(len(entries), func(i int) bool {
+		return !key.LessThan[Key](entries[i].key, k)
+	})
+	return i, i < len(entries) && key.EqualTo[Key](entries[i].key, k)
+}
@@ -0,0 +1,81 @@
+package bench_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/caravan/go-immutable-trie/bench"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStores(t *testing.T) {
+	for name, factory := range bench.Factories[string]() {
+		t.Run(name, func(t *testing.T) {
+			as := assert.New(t)
+			rng := rand.New(rand.NewSource(44))
+
+			s := factory()
+			ref := map[string]int{}
+			for i := 0; i < 5000; i++ {
+				k := fmt.Sprintf("%x", rng.Intn(2000))
+				switch rng.Intn(8) {
+				case 0, 1:
+					delete(ref, k)
+					s = s.Remove(k)
+				case 2:
+					k = k[:1]
+					for e := range ref {
+						if len(e) >= len(k) && e[:len(k)] == k {
+							delete(ref, e)
+						}
+					}
+					s = s.RemovePrefix(k)
+				default:
+					ref[k] = i
+					s = s.Put(k, i)
+				}
+			}
+
+			as.Equal(len(ref), s.Scan())
+			for k, v := range ref {
+				res, ok := s.Get(k)
+				as.True(ok, k)
+				as.Equal(v, res, k)
+			}
+			_, ok := s.Get("missing")
+			as.False(ok)
+
+			if !s.Ordered() {
+				return
+			}
+			var keys []string
+			for k := range ref {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, probe := range []string{"", "5", "80", "zz"} {
+				idx := sort.SearchStrings(keys, probe)
+				as.Equal(min(10, len(keys)-idx), s.From(probe, 10), probe)
+			}
+		})
+	}
+}
+
+func TestByteStores(t *testing.T) {
+	as := assert.New(t)
+
+	for name, factory := range bench.Factories[[]byte]() {
+		s := factory()
+		for i := 0; i < 500; i++ {
+			s = s.Put([]byte{byte(i % 7), byte(i)}, i)
+		}
+		s = s.RemovePrefix([]byte{3})
+		as.Equal(500-71, s.Scan(), name)
+
+		v, ok := s.Get([]byte{4, 4})
+		as.True(ok, name)
+		as.Equal(4, v, name)
+	}
+}
@@ -0,0 +1,132 @@
+package trie
+
+import "github.com/caravan/go-immutable-trie/key"
+
+type (
+	// BiMap is an immutable, one-to-one mapping of Keys to Values, which
+	// can be looked up in either direction. No two Keys map to the same
+	// Value. It's composed of two Tries that are kept in sync, one of them
+	// indexing the Keys and the other indexing the Values
+	BiMap[Key key.Keyable, Value key.Keyable] interface {
+		Get(Key) (Value, bool)
+		GetByValue(Value) (Key, bool)
+		Count() int
+		IsEmpty() bool
+		Put(Key, Value) BiMap[Key, Value]
+		Remove(Key) (Value, BiMap[Key, Value], bool)
+		RemoveValue(Value) (Key, BiMap[Key, Value], bool)
+		WithKeyPrefix(Key) Query[Key, Value]
+		WithValuePrefix(Value) Query[Value, Key]
+		Keys() Trie[Key, Value]
+		Values() Trie[Value, Key]
+		Inverse() BiMap[Value, Key]
+	}
+
+	biMap[Key key.Keyable, Value key.Keyable] struct {
+		forward Trie[Key, Value]
+		reverse Trie[Value, Key]
+	}
+)
+
+// NewBiMap returns a new empty BiMap, with both of its Tries configured by
+// the provided Options. A WithScore Option scores the Pairs of the Trie
+// returned by Keys, and is ignored by the Trie returned by Values
+func NewBiMap[Key key.Keyable, Value key.Keyable](
+	opts ...Option,
+) BiMap[Key, Value] {
+	return &biMap[Key, Value]{
+		forward: New[Key, Value](opts...),
+		reverse: New[Value, Key](withoutScore(opts)...),
+	}
+}
+
+func (b *biMap[Key, Value]) Get(k Key) (Value, bool) {
+	return b.forward.Get(k)
+}
+
+func (b *biMap[Key, Value]) GetByValue(v Value) (Key, bool) {
+	return b.reverse.Get(v)
+}
+
+func (b *biMap[Key, Value]) Count() int {
+	return b.forward.Count()
+}
+
+func (b *biMap[Key, Value]) IsEmpty() bool {
+	return b.forward.IsEmpty()
+}
+
+// Put maps a Key to a Value. To keep both sides unique, the Value the Key
+// previously mapped to and the Key the Value was previously mapped from
+// are removed
+func (b *biMap[Key, Value]) Put(k Key, v Value) BiMap[Key, Value] {
+	forward, reverse := b.forward, b.reverse
+	if old, ok := forward.Get(k); ok {
+		if key.EqualTo[Value](old, v) {
+			return b
+		}
+		_, reverse, _ = reverse.Remove(old)
+	}
+	if old, ok := reverse.Get(v); ok {
+		_, forward, _ = forward.Remove(old)
+	}
+	return &biMap[Key, Value]{
+		forward: forward.Put(k, v),
+		reverse: reverse.Put(v, k),
+	}
+}
+
+func (b *biMap[Key, Value]) Remove(k Key) (Value, BiMap[Key, Value], bool) {
+	v, forward, ok := b.forward.Remove(k)
+	if !ok {
+		return v, b, false
+	}
+	_, reverse, _ := b.reverse.Remove(v)
+	return v, &biMap[Key, Value]{forward: forward, reverse: reverse}, true
+}
+
+func (b *biMap[Key, Value]) RemoveValue(
+	v Value,
+) (Key, BiMap[Key, Value], bool) {
+	k, inv, ok := b.Inverse().Remove(v)
+	if !ok {
+		return k, b, false
+	}
+	return k, inv.Inverse(), true
+}
+
+// WithKeyPrefix returns a Query over the mappings whose Keys start with
+// the provided prefix, in Key order
+func (b *biMap[Key, Value]) WithKeyPrefix(prefix Key) Query[Key, Value] {
+	return withPrefix(b.forward, prefix)
+}
+
+// WithValuePrefix returns a Query over the mappings whose Values start
+// with the provided prefix, in Value order
+func (b *biMap[Key, Value]) WithValuePrefix(prefix Value) Query[Value, Key] {
+	return withPrefix(b.reverse, prefix)
+}
+
+// Keys returns the Trie that indexes the BiMap by Key
+func (b *biMap[Key, Value]) Keys() Trie[Key, Value] {
+	return b.forward
+}
+
+// Values returns the Trie that indexes the BiMap by Value
+func (b *biMap[Key, Value]) Values() Trie[Value, Key] {
+	return b.reverse
+}
+
+// Inverse returns the BiMap with its Keys and Values swapped. No copying
+// is performed
+func (b *biMap[Key, Value]) Inverse() BiMap[Value, Key] {
+	return &biMap[Value, Key]{forward: b.reverse, reverse: b.forward}
+}
+
+func withPrefix[Key key.Keyable, Value any](
+	t Trie[Key, Value], prefix Key,
+) Query[Key, Value] {
+	return t.Select().From(prefix).While(func(k Key, _ Value) bool {
+		return key.StartsWith(k, prefix)
+	})
+}
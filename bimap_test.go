@@ -0,0 +1,142 @@
+package trie_test
+
+import (
+	"math/rand"
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBiMap(t *testing.T) {
+	as := assert.New(t)
+
+	e := trie.NewBiMap[string, string]()
+	as.True(e.IsEmpty())
+	_, ok := e.GetByValue("x")
+	as.False(ok)
+
+	b := e.Put("go", "https://go.dev").Put("gh", "https://github.com")
+	as.Equal(2, b.Count())
+	v, ok := b.Get("go")
+	as.True(ok)
+	as.Equal("https://go.dev", v)
+	k, ok := b.GetByValue("https://github.com")
+	as.True(ok)
+	as.Equal("gh", k)
+	as.Same(b, b.Put("go", "https://go.dev"))
+	as.True(e.IsEmpty())
+
+	// re-mapping a Key releases its old Value
+	r := b.Put("go", "https://golang.org")
+	as.Equal(2, r.Count())
+	_, ok = r.GetByValue("https://go.dev")
+	as.False(ok)
+	k, _ = r.GetByValue("https://golang.org")
+	as.Equal("go", k)
+
+	// re-mapping a Value releases its old Key
+	r = b.Put("g", "https://go.dev")
+	as.Equal(2, r.Count())
+	_, ok = r.Get("go")
+	as.False(ok)
+	k, _ = r.GetByValue("https://go.dev")
+	as.Equal("g", k)
+
+	// both at once
+	r = b.Put("go", "https://github.com")
+	as.Equal(1, r.Count())
+	as.Equal(r.Keys().Count(), r.Values().Count())
+	_, ok = r.Get("gh")
+	as.False(ok)
+
+	v, r, ok = b.Remove("go")
+	as.True(ok)
+	as.Equal("https://go.dev", v)
+	as.Equal(1, r.Count())
+	_, ok = r.GetByValue("https://go.dev")
+	as.False(ok)
+	_, same, ok := r.Remove("go")
+	as.False(ok)
+	as.Same(r, same)
+
+	k, r, ok = b.RemoveValue("https://github.com")
+	as.True(ok)
+	as.Equal("gh", k)
+	_, ok = r.Get("gh")
+	as.False(ok)
+	_, same, ok = r.RemoveValue("https://github.com")
+	as.False(ok)
+	as.Same(r, same)
+
+	inv := b.Inverse()
+	v, _ = inv.Get("https://go.dev")
+	as.Equal("go", v)
+	as.Same(b.Keys(), inv.Values())
+}
+
+func TestBiMapPrefix(t *testing.T) {
+	as := assert.New(t)
+
+	b := trie.NewBiMap[string, string](trie.WithPathCompression()).
+		Put("a1", "z/x").
+		Put("a2", "y/x").
+		Put("b1", "z/y").
+		Put("a", "q")
+
+	var keys []string
+	b.WithKeyPrefix("a").ForEach(func(k string, _ string) {
+		keys = append(keys, k)
+	})
+	as.Equal([]string{"a", "a1", "a2"}, keys)
+
+	var values []string
+	b.WithValuePrefix("z/").ForEach(func(v string, k string) {
+		values = append(values, v+"="+k)
+	})
+	as.Equal([]string{"z/x=a1", "z/y=b1"}, values)
+	as.Equal(0, b.WithValuePrefix("w").Count())
+}
+
+func TestBiMapRandom(t *testing.T) {
+	as := assert.New(t)
+	rng := rand.New(rand.NewSource(47))
+
+	b := trie.NewBiMap[string, string]()
+	forward := map[string]string{}
+	for i := 0; i < 2000; i++ {
+		k := string(rune('a' + rng.Intn(16)))
+		v := string(rune('A' + rng.Intn(16)))
+		switch rng.Intn(4) {
+		case 0:
+			_, b, _ = b.Remove(k)
+			delete(forward, k)
+		case 1:
+			_, b, _ = b.RemoveValue(v)
+			for fk, fv := range forward {
+				if fv == v {
+					delete(forward, fk)
+				}
+			}
+		default:
+			b = b.Put(k, v)
+			for fk, fv := range forward {
+				if fv == v {
+					delete(forward, fk)
+				}
+			}
+			forward[k] = v
+		}
+	}
+
+	as.Equal(len(forward), b.Count())
+	as.Equal(len(forward), b.Values().Count())
+	for k, v := range forward {
+		got, ok := b.Get(k)
+		as.True(ok)
+		as.Equal(v, got)
+		got, ok = b.GetByValue(v)
+		as.True(ok)
+		as.Equal(k, got)
+	}
+}
@@ -0,0 +1,340 @@
+package trie
+
+import (
+	"iter"
+	"math"
+	"math/bits"
+	"slices"
+
+	"github.com/caravan/go-immutable-trie/key"
+	"github.com/caravan/go-immutable-trie/nibble"
+)
+
+type (
+	// buckets is a sparse array of child nodes, indexed by the key unit
+	// that leads to each child. Only the occupied slots are stored, in
+	// index order, alongside a bitmap of which slots they are. A node's
+	// buckets grow and shrink with its number of children rather than
+	// spanning the full width of its Strategy, so writes copy only what's
+	// occupied. Buckets are never modified once they've been built
+	buckets[Key key.Keyable, Value any] struct {
+		children []*trie[Key, Value]
+		extra    *bucketsExtra
+
+		// occupied is the bitmap of the first sixteen slots, which is
+		// every slot that a Strategy of up to four bits can reach
+		occupied uint16
+
+		// skip is the number of key units that the node skips before
+		// branching. It's only non-zero with path compression, and only
+		// a node with children needs it, so it's kept here
+		skip int32
+	}
+
+	// bucketsExtra holds what most buckets can do without: the bitmap of
+	// the slots beyond the first sixteen, which only a Strategy of more
+	// than four bits reaches, and the score annotation of a Trie that's
+	// configured WithScore. It's allocated only when one of them is used
+	bucketsExtra struct {
+		occupied bitmap
+		best     float64
+
+		// bests holds the highest score in each child's subtree, in the
+		// order of the children, so that a write scores only the child
+		// that it replaces rather than all of its siblings
+		bests []float64
+	}
+
+	// bitmap records which of the slots of a set of buckets are occupied
+	bitmap [nibble.MaxSize / 64]uint64
+)
+
+// narrowSlots is the number of slots whose occupancy is recorded without
+// allocating a bucketsExtra
+const narrowSlots = 16
+
+// makeBuckets compacts a dense array of child nodes, indexed by key unit,
+// into sparse buckets that skip the provided number of units. If no
+// children are present, nil is returned
+func makeBuckets[Key key.Keyable, Value any](
+	dense []*trie[Key, Value], skip int,
+) *buckets[Key, Value] {
+	res := &buckets[Key, Value]{skip: int32(skip)}
+	var occupied bitmap
+	for idx, child := range dense {
+		if child != nil {
+			occupied.set(uint8(idx))
+			res.children = append(res.children, child)
+		}
+	}
+	if res.children == nil {
+		return nil
+	}
+	res.setOccupied(occupied)
+	return res.annotate(nil)
+}
+
+func (b *buckets[Key, Value]) get(idx uint8) *trie[Key, Value] {
+	if b == nil {
+		return nil
+	}
+	if b.extra == nil {
+		mask := uint16(1) << (idx % narrowSlots)
+		if idx >= narrowSlots || b.occupied&mask == 0 {
+			return nil
+		}
+		return b.children[bits.OnesCount16(b.occupied&(mask-1))]
+	}
+	occupied := b.slots()
+	if !occupied.has(idx) {
+		return nil
+	}
+	return b.children[occupied.rank(idx)]
+}
+
+// with returns a copy of the buckets having the slot at idx hold the
+// provided child, or cleared if the child is nil. If no slots remain
+// occupied, nil is returned
+func (b *buckets[Key, Value]) with(
+	idx uint8, child *trie[Key, Value],
+) *buckets[Key, Value] {
+	res := &buckets[Key, Value]{}
+	if b != nil {
+		res.skip = b.skip
+	}
+	occupied := b.slots()
+	children := b.nodes()
+	bests := b.bests()
+	pos := occupied.rank(idx)
+	switch {
+	case occupied.has(idx) && child != nil:
+		res.children = make([]*trie[Key, Value], len(children))
+		copy(res.children, children)
+		res.children[pos] = child
+		if bests != nil {
+			bests = slices.Clone(bests)
+			bests[pos] = child.subtreeBest()
+		}
+	case occupied.has(idx):
+		if len(children) == 1 {
+			return nil
+		}
+		occupied.clear(idx)
+		res.children = make([]*trie[Key, Value], 0, len(children)-1)
+		res.children = append(res.children, children[:pos]...)
+		res.children = append(res.children, children[pos+1:]...)
+		if bests != nil {
+			bests = append(append(make([]float64, 0, len(bests)-1),
+				bests[:pos]...), bests[pos+1:]...)
+		}
+	case child != nil:
+		occupied.set(idx)
+		res.children = make([]*trie[Key, Value], 0, len(children)+1)
+		res.children = append(res.children, children[:pos]...)
+		res.children = append(res.children, child)
+		res.children = append(res.children, children[pos:]...)
+		if bests != nil {
+			bests = append(append(append(make([]float64, 0, len(bests)+1),
+				bests[:pos]...), child.subtreeBest()), bests[pos:]...)
+		}
+	default:
+		return b
+	}
+	res.setOccupied(occupied)
+	return res.annotate(bests)
+}
+
+// withSkip returns a copy of the buckets that skips the provided number
+// of key units
+func (b *buckets[Key, Value]) withSkip(skip int) *buckets[Key, Value] {
+	res := *b
+	res.skip = int32(skip)
+	return &res
+}
+
+// slots returns the bitmap of occupied slots of the buckets
+func (b *buckets[Key, Value]) slots() bitmap {
+	var res bitmap
+	if b == nil {
+		return res
+	}
+	if b.extra != nil {
+		res = b.extra.occupied
+	}
+	res[0] |= uint64(b.occupied)
+	return res
+}
+
+// setOccupied records the occupied slots of buckets that are being built,
+// allocating a bucketsExtra only if a slot is beyond the first sixteen
+func (b *buckets[Key, Value]) setOccupied(occupied bitmap) {
+	b.occupied = uint16(occupied[0])
+	occupied[0] &^= 1<<narrowSlots - 1
+	if occupied != (bitmap{}) {
+		b.ensureExtra().occupied = occupied
+	}
+}
+
+func (b *buckets[Key, Value]) ensureExtra() *bucketsExtra {
+	if b.extra == nil {
+		b.extra = &bucketsExtra{}
+	}
+	return b.extra
+}
+
+// annotate records the highest score found in each of the children's
+// subtrees, and in all of them, if the Trie is configured WithScore. The
+// provided bests are those carried over from the buckets being copied, or
+// nil if every child must be scored. It's only called while the buckets
+// are being built
+func (b *buckets[Key, Value]) annotate(bests []float64) *buckets[Key, Value] {
+	if bests == nil {
+		score, ok := scorer[Key, Value](b.children[0].cfg)
+		if !ok {
+			return b
+		}
+		bests = make([]float64, len(b.children))
+		for i, child := range b.children {
+			bests[i] = child.best(score)
+		}
+	}
+	extra := b.ensureExtra()
+	extra.bests = bests
+	extra.best = math.Inf(-1)
+	for _, best := range bests {
+		extra.best = max(extra.best, best)
+	}
+	return b
+}
+
+// best returns the score annotation of the buckets, which is only
+// recorded if the Trie is configured WithScore
+func (b *buckets[Key, Value]) best() float64 {
+	if b.extra == nil {
+		return math.Inf(-1)
+	}
+	return b.extra.best
+}
+
+// bests returns the score annotations of each of the children, or nil if
+// they aren't recorded
+func (b *buckets[Key, Value]) bests() []float64 {
+	if b == nil || b.extra == nil {
+		return nil
+	}
+	return b.extra.bests
+}
+
+// nodes returns the occupied slots of the buckets in index order
+func (b *buckets[Key, Value]) nodes() []*trie[Key, Value] {
+	if b == nil {
+		return nil
+	}
+	return b.children
+}
+
+// all iterates over the occupied slots of the buckets, and their indexes,
+// in index order
+func (b *buckets[Key, Value]) all() iter.Seq2[uint8, *trie[Key, Value]] {
+	return func(yield func(uint8, *trie[Key, Value]) bool) {
+		if b == nil {
+			return
+		}
+		occupied := b.slots()
+		idx := occupied.next(0)
+		for _, child := range b.children {
+			if !yield(uint8(idx), child) {
+				return
+			}
+			idx = occupied.next(idx + 1)
+		}
+	}
+}
+
+// next returns the first occupied slot at or after the provided index, or
+// -1 and nil if there isn't one
+func (b *buckets[Key, Value]) next(from int) (int, *trie[Key, Value]) {
+	if b == nil {
+		return -1, nil
+	}
+	if idx := b.slots().next(from); idx >= 0 {
+		return idx, b.get(uint8(idx))
+	}
+	return -1, nil
+}
+
+// prev returns the last occupied slot before the provided index, or -1 and
+// nil if there isn't one
+func (b *buckets[Key, Value]) prev(before int) (int, *trie[Key, Value]) {
+	if b == nil {
+		return -1, nil
+	}
+	if idx := b.slots().prev(before); idx >= 0 {
+		return idx, b.get(uint8(idx))
+	}
+	return -1, nil
+}
+
+func (m bitmap) has(idx uint8) bool {
+	return m[idx/64]&(1<<(idx%64)) != 0
+}
+
+func (m *bitmap) set(idx uint8) {
+	m[idx/64] |= 1 << (idx % 64)
+}
+
+func (m *bitmap) clear(idx uint8) {
+	m[idx/64] &^= 1 << (idx % 64)
+}
+
+// count returns the number of occupied slots
+func (m bitmap) count() int {
+	res := 0
+	for _, w := range m {
+		res += bits.OnesCount64(w)
+	}
+	return res
+}
+
+// rank returns the number of occupied slots that precede an index, which
+// is that index's position within the stored children
+func (m bitmap) rank(idx uint8) int {
+	res := 0
+	word := int(idx / 64)
+	for i := 0; i < word; i++ {
+		res += bits.OnesCount64(m[i])
+	}
+	return res + bits.OnesCount64(m[word]&(1<<(idx%64)-1))
+}
+
+func (m bitmap) next(from int) int {
+	if from < 0 {
+		from = 0
+	}
+	for word := from / 64; word < len(m); word++ {
+		w := m[word]
+		if word == from/64 {
+			w &^= 1<<(from%64) - 1
+		}
+		if w != 0 {
+			return word*64 + bits.TrailingZeros64(w)
+		}
+	}
+	return -1
+}
+
+func (m bitmap) prev(before int) int {
+	if before > nibble.MaxSize {
+		before = nibble.MaxSize
+	}
+	for word := (before - 1) / 64; before > 0 && word >= 0; word-- {
+		w := m[word]
+		if word == before/64 {
+			w &= 1<<(before%64) - 1
+		}
+		if w != 0 {
+			return word*64 + 63 - bits.LeadingZeros64(w)
+		}
+	}
+	return -1
+}
@@ -0,0 +1,98 @@
+package trie_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSparseBuckets(t *testing.T) {
+	for _, name := range []string{"default", "compressed"} {
+		for _, s := range strategies {
+			opts := append([]trie.Option{trie.WithStrategy(s)}, layouts[name]...)
+			t.Run(fmt.Sprintf("%s/%d-bit", name, s.Bits()), func(t *testing.T) {
+				as := assert.New(t)
+				rng := rand.New(rand.NewSource(38))
+
+				tr := trie.New[string, int](opts...)
+				for i := 0; i < 1000; i++ {
+					k := fmt.Sprintf("%x", rng.Intn(500))
+					if rng.Intn(3) == 0 {
+						_, tr, _ = tr.Remove(k)
+					} else {
+						tr = tr.Put(k, i)
+					}
+					// every node but the root occupies exactly one slot
+					as.Equal(max(tr.Count()-1, 0), slots(tr.Stats()))
+				}
+
+				for !tr.IsEmpty() {
+					tr = tr.Rest()
+					as.Equal(max(tr.Count()-1, 0), slots(tr.Stats()))
+				}
+			})
+		}
+	}
+}
+
+func TestSparseBucketsOrder(t *testing.T) {
+	as := assert.New(t)
+
+	for _, s := range strategies {
+		tr := trie.New[[]byte, int](trie.WithStrategy(s))
+		for _, b := range rand.New(rand.NewSource(38)).Perm(256) {
+			tr = tr.Put([]byte{byte(b)}, b)
+		}
+		i := 0
+		tr.Select().All().ForEach(func(k []byte, v int) {
+			as.Equal([]byte{byte(i)}, k)
+			as.Equal(i, v)
+			i++
+		})
+		as.Equal(256, i)
+
+		j := 255
+		tr.Select().Descending().All().ForEach(func(k []byte, v int) {
+			as.Equal(j, v)
+			j--
+		})
+		as.Equal(-1, j)
+	}
+}
+
+func TestBucketsExtra(t *testing.T) {
+	as := assert.New(t)
+
+	for _, s := range strategies {
+		tr := trie.New[[]byte, int](trie.WithStrategy(s))
+		for i := 0; i < 256; i++ {
+			tr = tr.Put([]byte{byte(i)}, i)
+		}
+		as.Nil(tr.Validate())
+		if s.Bits() <= 4 {
+			// sixteen slots cover every Strategy of up to four bits
+			as.Equal(0, trie.Extras(tr))
+		} else {
+			as.Equal(1, trie.Extras(tr))
+		}
+	}
+
+	byValue := func(_ string, v int) float64 { return float64(v) }
+	scored := trie.New[string, int](trie.WithScore(byValue))
+	for k, v := range testMap {
+		scored = scored.Put(k, v)
+	}
+	as.Equal(0, trie.Extras(makeTestTrie()))
+	as.Equal(scored.Stats().Branches, trie.Extras(scored))
+}
+
+func slots(s trie.Stats) int {
+	res := 0
+	for n, count := range s.Occupancy {
+		res += n * count
+	}
+	return res
+}
@@ -0,0 +1,8 @@
+package cidr
+
+import trie "github.com/caravan/go-immutable-trie"
+
+// Stats exposes the shape of the Trie underlying a Table
+func Stats[Value any](t Table[Value]) trie.Stats {
+	return t.(*table[Value]).trie.Stats()
+}
@@ -0,0 +1,169 @@
+package cidr
+
+import (
+	"iter"
+	"net/netip"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/caravan/go-immutable-trie/key"
+	"github.com/caravan/go-immutable-trie/nibble"
+)
+
+type (
+	// Table maps CIDR prefixes to Values, supporting bit-granular longest
+	// prefix matching of both IPv4 and IPv6 addresses in one immutable
+	// structure
+	Table[Value any] interface {
+		Get(netip.Prefix) (Value, bool)
+		Lookup(netip.Addr) (netip.Prefix, Value, bool)
+		Covering(netip.Prefix) iter.Seq2[netip.Prefix, Value]
+		Covered(netip.Prefix) iter.Seq2[netip.Prefix, Value]
+		All() iter.Seq2[netip.Prefix, Value]
+		Count() int
+		IsEmpty() bool
+		Insert(netip.Prefix, Value) Table[Value]
+		Remove(netip.Prefix) (Value, Table[Value], bool)
+	}
+
+	table[Value any] struct {
+		trie trie.Trie[[]byte, Value]
+	}
+)
+
+// Address families are encoded as the first byte of each Key, so that IPv4
+// and IPv6 prefixes occupy disjoint subtrees
+const (
+	familyIPv4 byte = 4
+	familyIPv6 byte = 6
+)
+
+// New returns a new empty Table instance. Each prefix bit occupies a
+// byte of its Key, so the Trie consumes a byte per level and compresses
+// paths, keeping a /128 as shallow as the prefixes around it
+func New[Value any]() Table[Value] {
+	return &table[Value]{
+		trie: trie.New[[]byte, Value](
+			trie.WithStrategy(nibble.Bits8),
+			trie.WithPathCompression(),
+		),
+	}
+}
+
+func (t *table[Value]) Get(p netip.Prefix) (Value, bool) {
+	if !p.IsValid() {
+		var zero Value
+		return zero, false
+	}
+	return t.trie.Get(encode(p))
+}
+
+func (t *table[Value]) Lookup(a netip.Addr) (netip.Prefix, Value, bool) {
+	if a.IsValid() {
+		k := encode(netip.PrefixFrom(a, a.BitLen()))
+		if p, ok := t.trie.LongestPrefix(k); ok {
+			return decode(p.Key()), p.Value(), true
+		}
+	}
+	var zero Value
+	return netip.Prefix{}, zero, false
+}
+
+func (t *table[Value]) Covering(p netip.Prefix) iter.Seq2[netip.Prefix, Value] {
+	return func(yield func(netip.Prefix, Value) bool) {
+		if !p.IsValid() {
+			return
+		}
+		for k, v := range t.trie.AllPrefixesOf(encode(p)) {
+			if !yield(decode(k), v) {
+				return
+			}
+		}
+	}
+}
+
+func (t *table[Value]) Covered(p netip.Prefix) iter.Seq2[netip.Prefix, Value] {
+	return func(yield func(netip.Prefix, Value) bool) {
+		if !p.IsValid() {
+			return
+		}
+		prefix := encode(p)
+		covered := t.trie.Select().From(prefix).While(
+			func(k []byte, _ Value) bool {
+				return key.StartsWith(k, prefix)
+			},
+		)
+		for e, q, ok := covered.Next(); ok; e, q, ok = q.Next() {
+			if !yield(decode(e.Key()), e.Value()) {
+				return
+			}
+		}
+	}
+}
+
+func (t *table[Value]) All() iter.Seq2[netip.Prefix, Value] {
+	return func(yield func(netip.Prefix, Value) bool) {
+		for e, q, ok := t.trie.Select().All().Next(); ok; e, q, ok = q.Next() {
+			if !yield(decode(e.Key()), e.Value()) {
+				return
+			}
+		}
+	}
+}
+
+func (t *table[_]) Count() int {
+	return t.trie.Count()
+}
+
+func (t *table[_]) IsEmpty() bool {
+	return t.trie.IsEmpty()
+}
+
+func (t *table[Value]) Insert(p netip.Prefix, v Value) Table[Value] {
+	if !p.IsValid() {
+		return t
+	}
+	return &table[Value]{
+		trie: t.trie.Put(encode(p), v),
+	}
+}
+
+func (t *table[Value]) Remove(p netip.Prefix) (Value, Table[Value], bool) {
+	if p.IsValid() {
+		if v, rest, ok := t.trie.Remove(encode(p)); ok {
+			return v, &table[Value]{trie: rest}, true
+		}
+	}
+	var zero Value
+	return zero, t, false
+}
+
+// encode converts a prefix into a Key holding its address family followed
+// by one byte for each of its significant bits. This sidesteps the nibble
+// granularity of the underlying Trie, allowing prefixes of any length to be
+// matched exactly
+func encode(p netip.Prefix) []byte {
+	p = p.Masked()
+	bits := p.Bits()
+	res := make([]byte, bits+1)
+	res[0] = familyIPv6
+	if p.Addr().Is4() {
+		res[0] = familyIPv4
+	}
+	addr := p.Addr().AsSlice()
+	for i := 0; i < bits; i++ {
+		res[i+1] = addr[i/8] >> (7 - i%8) & 1
+	}
+	return res
+}
+
+func decode(k []byte) netip.Prefix {
+	var addr [16]byte
+	bits := k[1:]
+	for i, b := range bits {
+		addr[i/8] |= b << (7 - i%8)
+	}
+	if k[0] == familyIPv4 {
+		return netip.PrefixFrom(netip.AddrFrom4([4]byte(addr[:4])), len(bits))
+	}
+	return netip.PrefixFrom(netip.AddrFrom16(addr), len(bits))
+}
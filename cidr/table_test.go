@@ -0,0 +1,164 @@
+package cidr_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/caravan/go-immutable-trie/cidr"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeTestTable() cidr.Table[string] {
+	res := cidr.New[string]()
+	for p, v := range map[string]string{
+		"0.0.0.0/0":      "default4",
+		"10.0.0.0/8":     "corp",
+		"10.8.0.0/13":    "vpn",
+		"10.8.4.0/22":    "lab",
+		"192.168.1.0/24": "home",
+		"::/0":           "default6",
+		"2001:db8::/32":  "doc",
+		"2001:db8::/33":  "doc-low",
+	} {
+		res = res.Insert(netip.MustParsePrefix(p), v)
+	}
+	return res
+}
+
+func TestLookup(t *testing.T) {
+	as := assert.New(t)
+
+	tbl := makeTestTable()
+	as.Equal(8, tbl.Count())
+
+	for addr, expected := range map[string]string{
+		"10.8.4.1":         "10.8.4.0/22",
+		"10.8.7.255":       "10.8.4.0/22",
+		"10.8.8.1":         "10.8.0.0/13",
+		"10.15.255.255":    "10.8.0.0/13",
+		"10.16.0.0":        "10.0.0.0/8",
+		"11.0.0.1":         "0.0.0.0/0",
+		"192.168.1.77":     "192.168.1.0/24",
+		"2001:db8::1":      "2001:db8::/33",
+		"2001:db8:8000::1": "2001:db8::/32",
+		"2001:db9::1":      "::/0",
+	} {
+		p, _, ok := tbl.Lookup(netip.MustParseAddr(addr))
+		as.True(ok, addr)
+		as.Equal(netip.MustParsePrefix(expected), p, addr)
+	}
+
+	_, v, ok := tbl.Lookup(netip.MustParseAddr("10.9.0.1"))
+	as.True(ok)
+	as.Equal("vpn", v)
+
+	_, _, ok = cidr.New[string]().Lookup(netip.MustParseAddr("10.0.0.1"))
+	as.False(ok)
+
+	_, _, ok = tbl.Lookup(netip.Addr{})
+	as.False(ok)
+}
+
+func TestGetInsertRemove(t *testing.T) {
+	as := assert.New(t)
+
+	t1 := makeTestTable()
+	v, ok := t1.Get(netip.MustParsePrefix("10.8.0.0/13"))
+	as.True(ok)
+	as.Equal("vpn", v)
+
+	v, ok = t1.Get(netip.MustParsePrefix("10.9.1.2/13"))
+	as.True(ok)
+	as.Equal("vpn", v)
+
+	_, ok = t1.Get(netip.MustParsePrefix("10.8.0.0/14"))
+	as.False(ok)
+
+	v, t2, ok := t1.Remove(netip.MustParsePrefix("10.8.0.0/13"))
+	as.True(ok)
+	as.Equal("vpn", v)
+	as.Equal(7, t2.Count())
+
+	p, _, _ := t2.Lookup(netip.MustParseAddr("10.9.0.1"))
+	as.Equal(netip.MustParsePrefix("10.0.0.0/8"), p)
+
+	// the original version is unaffected
+	as.Equal(8, t1.Count())
+	p, _, _ = t1.Lookup(netip.MustParseAddr("10.9.0.1"))
+	as.Equal(netip.MustParsePrefix("10.8.0.0/13"), p)
+
+	_, t3, ok := t2.Remove(netip.MustParsePrefix("10.8.0.0/13"))
+	as.False(ok)
+	as.Equal(t2, t3)
+
+	as.Equal(t1, t1.Insert(netip.Prefix{}, "bogus"))
+	as.True(cidr.New[int]().IsEmpty())
+}
+
+func TestDepth(t *testing.T) {
+	as := assert.New(t)
+
+	tbl := cidr.New[int]().Insert(netip.MustParsePrefix("::/0"), -1)
+	base := netip.MustParseAddr("2001:db8::").As16()
+	for i := 0; i < 256; i++ {
+		a := base
+		a[15] = byte(i)
+		host := netip.PrefixFrom(netip.AddrFrom16(a), 128)
+		tbl = tbl.Insert(host, i)
+	}
+	as.Equal(257, tbl.Count())
+	as.LessOrEqual(cidr.Stats(tbl).MaxDepth, 16)
+
+	p, v, ok := tbl.Lookup(netip.MustParseAddr("2001:db8::ff"))
+	as.True(ok)
+	as.Equal(netip.MustParsePrefix("2001:db8::ff/128"), p)
+	as.Equal(255, v)
+}
+
+func TestCovering(t *testing.T) {
+	as := assert.New(t)
+
+	var res []string
+	tbl := makeTestTable()
+	for p, v := range tbl.Covering(netip.MustParsePrefix("10.8.5.0/24")) {
+		res = append(res, p.String()+"="+v)
+	}
+	as.Equal([]string{
+		"0.0.0.0/0=default4",
+		"10.0.0.0/8=corp",
+		"10.8.0.0/13=vpn",
+		"10.8.4.0/22=lab",
+	}, res)
+
+	res = nil
+	for p := range tbl.Covering(netip.MustParsePrefix("2001:db8::/32")) {
+		res = append(res, p.String())
+	}
+	as.Equal([]string{"::/0", "2001:db8::/32"}, res)
+}
+
+func TestCovered(t *testing.T) {
+	as := assert.New(t)
+
+	var res []string
+	tbl := makeTestTable()
+	for p := range tbl.Covered(netip.MustParsePrefix("10.0.0.0/8")) {
+		res = append(res, p.String())
+	}
+	as.Equal([]string{"10.0.0.0/8", "10.8.0.0/13", "10.8.4.0/22"}, res)
+
+	res = nil
+	for p := range tbl.Covered(netip.MustParsePrefix("0.0.0.0/0")) {
+		res = append(res, p.String())
+		if len(res) == 2 {
+			break
+		}
+	}
+	as.Equal([]string{"0.0.0.0/0", "10.0.0.0/8"}, res)
+
+	cnt := 0
+	for range tbl.All() {
+		cnt++
+	}
+	as.Equal(8, cnt)
+}
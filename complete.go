@@ -0,0 +1,228 @@
+package trie
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/caravan/go-immutable-trie/key"
+	"github.com/caravan/go-immutable-trie/nibble"
+)
+
+type (
+	// completion is either a scored Pair or, if node is set, a subtree
+	// whose score is the highest of any Pair within it
+	completion[Key key.Keyable, Value any] struct {
+		pair  *pair[Key, Value]
+		node  *trie[Key, Value]
+		n     nibble.Nibbles[Key]
+		score float64
+	}
+
+	// completions is a heap of completions, ordered best first or, if
+	// worst is set, worst first
+	completions[Key key.Keyable, Value any] struct {
+		items []*completion[Key, Value]
+		worst bool
+	}
+)
+
+// Complete returns up to limit Pairs whose Keys start with the provided
+// prefix, ordered from the highest score to the lowest, and by Key among
+// equal scores. If score is nil, the function the Trie was configured
+// WithScore is used, and subtrees whose scores can't place are skipped
+// without being visited. If there's no such function, nil is returned, so
+// Scored should be consulted to tell that apart from there being no
+// completions. Otherwise, every Pair with the prefix is scored
+func (t *trie[Key, Value]) Complete(
+	prefix Key, limit int, score func(Key, Value) float64,
+) []Pair[Key, Value] {
+	if limit < 1 {
+		return nil
+	}
+	if score != nil {
+		return completeScan(t, prefix, limit, score)
+	}
+	if score, ok := scorer[Key, Value](t.cfg); ok {
+		return t.completeRanked(prefix, limit, score)
+	}
+	return nil
+}
+
+// completeRanked performs a best-first search of the Trie, expanding a
+// subtree only once its annotated score is the best remaining
+func (t *trie[Key, Value]) completeRanked(
+	prefix Key, limit int, score func(Key, Value) float64,
+) []Pair[Key, Value] {
+	var res []Pair[Key, Value]
+	pending := &completions[Key, Value]{}
+	heap.Push(pending, &completion[Key, Value]{
+		node:  t,
+		n:     t.nibbles(prefix),
+		score: t.best(score),
+	})
+	for len(res) < limit && pending.Len() > 0 {
+		c := heap.Pop(pending).(*completion[Key, Value])
+		if c.node == nil {
+			res = append(res, c.pair)
+			continue
+		}
+		node := c.node
+		if key.StartsWith(node.key, prefix) {
+			p := node.pair
+			heap.Push(pending, &completion[Key, Value]{
+				pair:  &p,
+				score: score(p.key, p.value),
+			})
+		}
+		n, ok := node.narrow(c.n)
+		if !ok {
+			continue
+		}
+		buckets, n := node.candidates(n)
+		for _, bucket := range buckets {
+			heap.Push(pending, &completion[Key, Value]{
+				node:  bucket,
+				n:     n,
+				score: bucket.best(score),
+			})
+		}
+	}
+	return res
+}
+
+// completeScan scores every Pair with the prefix, retaining the best
+func completeScan[Key key.Keyable, Value any](
+	t Trie[Key, Value], prefix Key, limit int, score func(Key, Value) float64,
+) []Pair[Key, Value] {
+	kept := &completions[Key, Value]{worst: true}
+	withPrefix(t, prefix).ForEach(func(k Key, v Value) {
+		c := &completion[Key, Value]{
+			pair:  &pair[Key, Value]{k, v},
+			score: score(k, v),
+		}
+		if kept.Len() < limit {
+			heap.Push(kept, c)
+		} else if c.better(kept.items[0]) {
+			kept.items[0] = c
+			heap.Fix(kept, 0)
+		}
+	})
+	res := make([]Pair[Key, Value], kept.Len())
+	for i := len(res) - 1; i >= 0; i-- {
+		res[i] = heap.Pop(kept).(*completion[Key, Value]).pair
+	}
+	return res
+}
+
+// narrow advances the Nibbles of a prefix past the run of units that this
+// node skips, reporting whether the Keys in its buckets can start with
+// the prefix. They can if the prefix is exhausted within the run
+func (t *trie[Key, Value]) narrow(
+	n nibble.Nibbles[Key],
+) (nibble.Nibbles[Key], bool) {
+	k := n.Branch(t.key)
+	for i := 0; i < t.skip(); i++ {
+		pu, pn, ok := n.Consume()
+		if !ok {
+			return n, true
+		}
+		ku, kn, _ := k.Consume()
+		if pu != ku {
+			return n, false
+		}
+		n, k = pn, kn
+	}
+	return n, true
+}
+
+// candidates returns the buckets whose Keys can start with a prefix,
+// advancing its Nibbles to their position. If the prefix is exhausted,
+// that's all of them
+func (t *trie[Key, Value]) candidates(
+	n nibble.Nibbles[Key],
+) ([]*trie[Key, Value], nibble.Nibbles[Key]) {
+	idx, next, ok := n.Consume()
+	if !ok {
+		return t.buckets.nodes(), n
+	}
+	if bucket := t.buckets.get(idx); bucket != nil {
+		return []*trie[Key, Value]{bucket}, next
+	}
+	return nil, next
+}
+
+// subtreeBest returns the highest score of any Pair in this node's subtree,
+// scoring only the node's own Pair
+func (t *trie[Key, Value]) subtreeBest() float64 {
+	score, ok := scorer[Key, Value](t.cfg)
+	if !ok {
+		return math.Inf(-1)
+	}
+	return t.best(score)
+}
+
+// best returns the highest score of any Pair in this node's subtree
+func (t *trie[Key, Value]) best(score func(Key, Value) float64) float64 {
+	res := score(t.key, t.value)
+	if t.buckets != nil {
+		res = max(res, t.buckets.best())
+	}
+	return res
+}
+
+// better reports whether a completion ranks ahead of another. A subtree
+// ranks ahead of a Pair with an equal score, as it may hold a lesser Key
+func (c *completion[Key, Value]) better(o *completion[Key, Value]) bool {
+	switch {
+	case c.score != o.score:
+		return c.score > o.score
+	case c.node != nil || o.node != nil:
+		return c.node != nil && o.node == nil
+	default:
+		return key.LessThan[Key](c.pair.key, o.pair.key)
+	}
+}
+
+func (c *completions[Key, Value]) Len() int {
+	return len(c.items)
+}
+
+func (c *completions[Key, Value]) Less(i, j int) bool {
+	if c.worst {
+		return c.items[j].better(c.items[i])
+	}
+	return c.items[i].better(c.items[j])
+}
+
+func (c *completions[Key, Value]) Swap(i, j int) {
+	c.items[i], c.items[j] = c.items[j], c.items[i]
+}
+
+func (c *completions[Key, Value]) Push(x any) {
+	c.items = append(c.items, x.(*completion[Key, Value]))
+}
+
+func (c *completions[Key, Value]) Pop() any {
+	last := len(c.items) - 1
+	res := c.items[last]
+	c.items = c.items[:last]
+	return res
+}
+
+// Scored returns whether the Trie was configured WithScore, so that
+// Complete can rank its Pairs without being given a score function
+func (t *trie[Key, Value]) Scored() bool {
+	_, ok := scorer[Key, Value](t.cfg)
+	return ok
+}
+
+func (e empty[Key, Value]) Scored() bool {
+	_, ok := scorer[Key, Value](e.cfg)
+	return ok
+}
+
+func (empty[Key, Value]) Complete(
+	Key, int, func(Key, Value) float64,
+) []Pair[Key, Value] {
+	return nil
+}
@@ -0,0 +1,261 @@
+package trie_test
+
+import (
+	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/caravan/go-immutable-trie/nibble"
+	"github.com/stretchr/testify/assert"
+)
+
+func byValue(_ string, v int) float64 {
+	return float64(v)
+}
+
+func completed(pairs []trie.Pair[string, int]) []string {
+	res := []string{}
+	for _, p := range pairs {
+		res = append(res, fmt.Sprintf("%s=%d", p.Key(), p.Value()))
+	}
+	return res
+}
+
+// expectCompletions ranks every Pair with the prefix the slow way
+func expectCompletions(
+	tr trie.Trie[string, int], prefix string, limit int,
+) []string {
+	var all []trie.Pair[string, int]
+	tr.Select().All().ForEach(func(k string, v int) {
+		if strings.HasPrefix(k, prefix) {
+			all = append(all, tr.Select().From(k).First())
+		}
+	})
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Value() > all[j].Value()
+	})
+	return completed(all[:min(limit, len(all))])
+}
+
+func TestComplete(t *testing.T) {
+	as := assert.New(t)
+
+	tr := trie.From(map[string]int{
+		"go":      5,
+		"golang":  9,
+		"gopher":  7,
+		"google":  9,
+		"goto":    1,
+		"grep":    8,
+		"haskell": 3,
+	})
+	as.Equal([]string{"golang=9", "google=9", "gopher=7"},
+		completed(tr.Complete("go", 3, byValue)))
+	as.Equal([]string{"golang=9", "google=9", "grep=8", "gopher=7"},
+		completed(tr.Complete("g", 4, byValue)))
+	as.Equal([]string{"goto=1", "go=5"},
+		completed(tr.Complete("go", 2, func(_ string, v int) float64 {
+			return -float64(v)
+		})))
+	as.Len(tr.Complete("", 100, byValue), 7)
+	as.Empty(tr.Complete("x", 3, byValue))
+	as.Empty(tr.Complete("go", 0, byValue))
+	as.Empty(trie.New[string, int]().Complete("go", 3, byValue))
+
+	as.Nil(tr.Complete("go", 3, nil))
+
+	as.PanicsWithValue(
+		"programmer error: score function doesn't match the Trie's types",
+		func() {
+			trie.New[string, int](trie.WithScore(
+				func(_ string, v float64) float64 { return v },
+			))
+		},
+	)
+	as.PanicsWithValue(
+		"programmer error: score function doesn't match the Trie's types",
+		func() {
+			trie.FromParallel(maps.All(map[string]int{}), 1,
+				trie.WithScore(func(_ []byte, v int) float64 {
+					return float64(v)
+				}),
+			)
+		},
+	)
+}
+
+func TestCompleteRanked(t *testing.T) {
+	for name, opts := range layouts {
+		t.Run(name, func(t *testing.T) {
+			as := assert.New(t)
+			rng := rand.New(rand.NewSource(48))
+
+			calls := 0
+			counted := func(_ string, v int) float64 {
+				calls++
+				return float64(v)
+			}
+			opts := append(slices.Clone(opts), trie.WithScore(counted))
+			tr := trie.New[string, int](opts...)
+			for i := 0; i < 3000; i++ {
+				k := randomKey(rng)
+				switch rng.Intn(6) {
+				case 0:
+					_, tr, _ = tr.Remove(k)
+				case 1:
+					tr, _ = tr.RemovePrefix(k)
+				default:
+					tr = tr.Put(k, rng.Intn(1000))
+				}
+			}
+			as.Nil(tr.Validate())
+
+			for _, prefix := range []string{
+				"", "/", "/api/v1/", "/api/v1/t", "/api/v2/a", "a", "/x",
+			} {
+				for _, limit := range []int{1, 5, 50} {
+					expected := expectCompletions(tr, prefix, limit)
+					as.Equal(expected,
+						completed(tr.Complete(prefix, limit, nil)),
+						"%s %d", prefix, limit)
+					as.Equal(expected,
+						completed(tr.Complete(prefix, limit, byValue)),
+						"%s %d", prefix, limit)
+				}
+			}
+
+			for i := 0; i < 10000; i++ {
+				tr = tr.Put(fmt.Sprintf("key-%d", i), rng.Intn(1000))
+			}
+			calls = 0
+			as.Equal(expectCompletions(tr, "key-", 5),
+				completed(tr.Complete("key-", 5, nil)))
+			as.Less(calls, tr.Count()/10)
+		})
+	}
+}
+
+func TestCompleteAnnotationCost(t *testing.T) {
+	as := assert.New(t)
+
+	calls := 0
+	counted := func(_ []byte, v int) float64 {
+		calls++
+		return float64(v)
+	}
+	tr := trie.New[[]byte, int](
+		trie.WithStrategy(nibble.Bits8), trie.WithScore(counted),
+	)
+	for i := 0; i < 256; i++ {
+		tr = tr.Put([]byte{byte(i), 0}, i)
+		tr = tr.Put([]byte{byte(i), 1}, i)
+	}
+	as.Nil(tr.Validate())
+
+	// a write scores each node on its path once, not all of their siblings
+	calls = 0
+	tr = tr.Put([]byte{7, 2}, 1000)
+	as.LessOrEqual(calls, 4)
+	calls = 0
+	_, tr, _ = tr.Remove([]byte{9, 1})
+	as.LessOrEqual(calls, 4)
+	as.Nil(tr.Validate())
+	as.Equal([]int{1000, 255},
+		valuesOf(tr.Complete(nil, 2, nil)))
+}
+
+func valuesOf(pairs []trie.Pair[[]byte, int]) []int {
+	var res []int
+	for _, p := range pairs {
+		res = append(res, p.Value())
+	}
+	return res
+}
+
+func TestCompleteAnnotations(t *testing.T) {
+	as := assert.New(t)
+	rng := rand.New(rand.NewSource(48))
+
+	tr := trie.New[string, int](trie.WithScore(byValue))
+	for i := 0; i < 1000; i++ {
+		tr = tr.Put(randomKey(rng), rng.Intn(1000))
+	}
+	as.Nil(tr.Validate())
+
+	lo, hi := tr.SplitAt("/api/v1/b")
+	as.Nil(lo.Validate())
+	as.Nil(hi.Validate())
+	joined := trie.Join(lo, hi)
+	as.Nil(joined.Validate())
+	as.Equal(expectCompletions(tr, "/api", 10),
+		completed(joined.Complete("/api", 10, nil)))
+
+	filtered := trie.FilterTrie(tr, func(_ string, v int) bool {
+		return v%2 == 0
+	})
+	as.Nil(filtered.Validate())
+	as.Equal(expectCompletions(filtered, "", 10),
+		completed(filtered.Complete("", 10, nil)))
+
+	negated := trie.MapValues(tr, func(_ string, v int) int {
+		return -v
+	})
+	as.Nil(negated.Validate())
+	as.Equal(expectCompletions(negated, "", 10),
+		completed(negated.Complete("", 10, nil)))
+}
+
+func TestCompleteUnscored(t *testing.T) {
+	as := assert.New(t)
+
+	tr := trie.New[string, int]()
+	as.False(tr.Scored())
+	as.Nil(tr.Complete("", 5, nil))
+	tr = tr.Put("a", 1)
+	as.False(tr.Scored())
+	as.Nil(tr.Complete("", 5, nil))
+	as.Equal([]string{"a=1"}, completed(tr.Complete("", 5, byValue)))
+
+	scored := trie.New[string, int](trie.WithScore(byValue))
+	as.True(scored.Scored())
+	as.True(scored.Put("a", 1).Scored())
+}
+
+func TestScoredDerivedTries(t *testing.T) {
+	as := assert.New(t)
+
+	opts := []trie.Option{
+		trie.WithScore(func(k string, _ []byte) float64 {
+			return float64(len(k))
+		}),
+	}
+	as.NotPanics(func() {
+		b := trie.NewBiMap[string, []byte](opts...).Put("ab", []byte("x"))
+		as.True(b.Keys().Scored())
+		as.False(b.Values().Scored())
+		as.Equal(2, len(b.Keys().Complete("a", 1, nil)[0].Key()))
+	})
+
+	same := []trie.Option{trie.WithScore(func(k, _ string) float64 {
+		return float64(len(k))
+	})}
+	as.NotPanics(func() {
+		b := trie.NewBiMap[string, string](same...).Put("ab", "x")
+		as.True(b.Keys().Scored())
+		as.False(b.Values().Scored())
+		as.Nil(b.Values().Validate())
+	})
+
+	as.NotPanics(func() {
+		trie.NewSet[string](opts...).Add("a")
+		trie.SetOf([]string{"a", "b"}, same...)
+		trie.NewMultiMap[string, string](same...).Add("a", "b")
+		trie.NewMultiMapWith[string](trie.IntegerCodec[int](), opts...).
+			Add("a", 1)
+	})
+}
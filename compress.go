@@ -0,0 +1,140 @@
+package trie
+
+import (
+	"math"
+
+	"github.com/caravan/go-immutable-trie/key"
+	"github.com/caravan/go-immutable-trie/nibble"
+)
+
+// compress prepares a node for the insertion of a Pair when path
+// compression is enabled. A leaf branches immediately, skipping the run of
+// units it shares with the new Key. If the new Key diverges within a
+// node's skipped run, the node is split at that point. Either result is
+// final
+func (t *trie[Key, Value]) compress(
+	p *pair[Key, Value], n nibble.Nibbles[Key],
+) (*trie[Key, Value], bool) {
+	if t.buckets == nil {
+		common := sharedUnits(n, n.Branch(t.key), math.MaxInt)
+		return t.branch(p, n, common), true
+	}
+	skip := t.skip()
+	if common := sharedUnits(n, n.Branch(t.key), skip); common < skip {
+		return t.splitPath(p, n, common), true
+	}
+	return t, false
+}
+
+// branch turns a leaf into a node that skips the units its Key shares with
+// a new Pair, holding the greater of the two Pairs in its buckets
+func (t *trie[Key, Value]) branch(
+	p *pair[Key, Value], n nibble.Nibbles[Key], common int,
+) *trie[Key, Value] {
+	lo, hi := p, &t.pair
+	if key.LessThan[Key](t.key, p.key) {
+		lo, hi = &t.pair, p
+	}
+	if idx, _, ok := skipUnits(n.Branch(hi.key), common).Consume(); ok {
+		res := t.leaf(lo)
+		res.buckets = res.buckets.with(idx, t.leaf(hi)).withSkip(common)
+		return res
+	}
+	panic("programmer error: branched on a non-consumable key")
+}
+
+// splitPath introduces a node at the unit where a new Key diverges from the
+// skipped run of an existing node. The existing node becomes a child of the
+// new node, keeping whatever remains of its run
+func (t *trie[Key, Value]) splitPath(
+	p *pair[Key, Value], n nibble.Nibbles[Key], common int,
+) *trie[Key, Value] {
+	n = skipUnits(n, common)
+	idx, _, _ := n.Branch(t.key).Consume()
+	rest := t.withSkip(t.skip() - common - 1)
+	res := &trie[Key, Value]{cfg: t.cfg}
+	if key.LessThan[Key](p.key, t.key) {
+		res.pair = *p
+		res.buckets = res.buckets.with(idx, rest).withSkip(common)
+		return res
+	}
+	res.pair = t.pair
+	res.buckets = res.buckets.with(idx, rest.promote())
+	if idx, _, ok := n.Consume(); ok {
+		res.buckets = res.buckets.with(idx, t.leaf(p)).withSkip(common)
+		return res
+	}
+	panic("programmer error: split on a non-consumable key")
+}
+
+// skip returns the number of key units that this node skips before its
+// children branch. Only a node with children can skip
+func (t *trie[Key, Value]) skip() int {
+	if t.buckets == nil {
+		return 0
+	}
+	return int(t.buckets.skip)
+}
+
+// diverges reports whether a Key, positioned at the start of this node,
+// departs from the run of units that the node skips. If it does, no Key
+// in the node's subtree shares a path with it
+func (t *trie[Key, Value]) diverges(n nibble.Nibbles[Key]) bool {
+	skip := t.skip()
+	return skip > 0 && sharedUnits(n, n.Branch(t.key), skip) < skip
+}
+
+// skipped advances Nibbles past the run of units that this node skips
+func (t *trie[Key, Value]) skipped(n nibble.Nibbles[Key]) nibble.Nibbles[Key] {
+	return skipUnits(n, t.skip())
+}
+
+func (t *trie[Key, Value]) withSkip(skip int) *trie[Key, Value] {
+	if t.skip() == skip {
+		return t
+	}
+	res := *t
+	res.buckets = t.buckets.withSkip(skip)
+	return &res
+}
+
+// expand converts a node that skips units into an equivalent node that
+// branches immediately, so that it can be merged with uncompressed nodes
+// at the same position
+func (t *trie[Key, Value]) expand(n nibble.Nibbles[Key]) *trie[Key, Value] {
+	if t.skip() == 0 {
+		return t
+	}
+	res := &trie[Key, Value]{
+		pair: t.pair,
+		cfg:  t.cfg,
+	}
+	if rest := t.withSkip(t.skip() - 1).promote(); rest != nil {
+		idx, _, _ := n.Branch(t.key).Consume()
+		res.buckets = res.buckets.with(idx, rest)
+	}
+	return res
+}
+
+func skipUnits[Key key.Keyable](
+	n nibble.Nibbles[Key], count int,
+) nibble.Nibbles[Key] {
+	for i := 0; i < count; i++ {
+		_, n, _ = n.Consume()
+	}
+	return n
+}
+
+// sharedUnits counts the units, up to a limit, that two sets of Nibbles
+// have in common before they diverge or either is exhausted
+func sharedUnits[Key key.Keyable](l, r nibble.Nibbles[Key], limit int) int {
+	for res := 0; res < limit; res++ {
+		lv, ln, lok := l.Consume()
+		rv, rn, rok := r.Consume()
+		if !lok || !rok || lv != rv {
+			return res
+		}
+		l, r = ln, rn
+	}
+	return limit
+}
@@ -0,0 +1,188 @@
+package trie_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/caravan/go-immutable-trie/nibble"
+	"github.com/stretchr/testify/assert"
+)
+
+var layouts = map[string][]trie.Option{
+	"default":      nil,
+	"compressed":   {trie.WithPathCompression()},
+	"compressed-1": {trie.WithPathCompression(), trie.WithStrategy(nibble.Bits1)},
+	"compressed-8": {trie.WithPathCompression(), trie.WithStrategy(nibble.Bits8)},
+}
+
+func makeTenantKey(i int) string {
+	return fmt.Sprintf("/api/v1/tenants/%08d/config", i)
+}
+
+func sortedKeys(m map[string]int) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func assertKeys(as *assert.Assertions, expected, actual []string, msg string) {
+	if len(expected) == 0 {
+		as.Empty(actual, msg)
+		return
+	}
+	as.Equal(expected, actual, msg)
+}
+
+func randomKey(rng *rand.Rand) string {
+	prefixes := []string{"", "/api/v1/", "/api/v1/tenants/", "/api/v2/"}
+	var sb strings.Builder
+	sb.WriteString(prefixes[rng.Intn(len(prefixes))])
+	for i := rng.Intn(4); i > 0; i-- {
+		sb.WriteByte("abc/"[rng.Intn(4)])
+	}
+	return sb.String()
+}
+
+func TestCompressedLayouts(t *testing.T) {
+	for name, opts := range layouts {
+		t.Run(name, func(t *testing.T) {
+			as := assert.New(t)
+			rng := rand.New(rand.NewSource(37))
+
+			m := map[string]int{}
+			tr := trie.New[string, int](opts...)
+			for i := 0; i < 3000; i++ {
+				k := randomKey(rng)
+				switch op := rng.Intn(10); {
+				case op < 6:
+					m[k] = i
+					tr = tr.Put(k, i)
+				case op < 9:
+					_, exists := m[k]
+					delete(m, k)
+					_, r, ok := tr.Remove(k)
+					as.Equal(exists, ok, k)
+					tr = r
+				default:
+					removed := false
+					for e := range m {
+						if strings.HasPrefix(e, k) {
+							delete(m, e)
+							removed = true
+						}
+					}
+					r, ok := tr.RemovePrefix(k)
+					as.Equal(removed, ok, k)
+					tr = r
+				}
+			}
+
+			keys := sortedKeys(m)
+			as.Equal(len(keys), tr.Count())
+			assertKeys(as, keys, tr.Select().All().Keys(), "all")
+			for k, v := range m {
+				res, ok := tr.Get(k)
+				as.True(ok)
+				as.Equal(v, res)
+			}
+
+			for i := 0; i < 200; i++ {
+				probe := randomKey(rng)
+				idx := sort.SearchStrings(keys, probe)
+				assertKeys(as, keys[idx:], tr.Select().From(probe).Keys(), probe)
+
+				var desc []string
+				for j := idx - 1; j >= 0; j-- {
+					desc = append(desc, keys[j])
+				}
+				if idx < len(keys) && keys[idx] == probe {
+					desc = append([]string{probe}, desc...)
+				}
+				assertKeys(as, desc, tr.Select().Descending().From(probe).Keys(), probe)
+
+				var prefixes []string
+				for _, k := range keys {
+					if strings.HasPrefix(probe, k) {
+						prefixes = append(prefixes, k)
+					}
+				}
+				var found []string
+				for k := range tr.AllPrefixesOf(probe) {
+					found = append(found, k)
+				}
+				assertKeys(as, prefixes, found, probe)
+
+				lo, hi := tr.SplitAt(probe)
+				assertKeys(as, keys[:idx], lo.Select().All().Keys(), probe)
+				assertKeys(as, keys[idx:], hi.Select().All().Keys(), probe)
+				assertKeys(as, keys, trie.Join(lo, hi).Select().All().Keys(), probe)
+			}
+		})
+	}
+}
+
+func TestCompressedDepth(t *testing.T) {
+	as := assert.New(t)
+
+	plain := trie.New[string, int]()
+	compressed := trie.New[string, int](trie.WithPathCompression())
+	for i := 0; i < 1000; i++ {
+		plain = plain.Put(makeTenantKey(i), i)
+		compressed = compressed.Put(makeTenantKey(i), i)
+	}
+	as.Equal(plain.Select().All().Keys(), compressed.Select().All().Keys())
+	as.Less(compressed.Stats().MaxDepth, plain.Stats().MaxDepth/4)
+
+	r, ok := compressed.RemovePrefix("/api/v1/tenants/000005")
+	as.True(ok)
+	as.Equal(900, r.Count())
+
+	f := trie.FilterTrie(compressed, func(_ string, v int) bool {
+		return v%2 == 0
+	})
+	as.Equal(500, f.Count())
+	as.Equal(makeTenantKey(998), f.Select().Descending().All().First().Key())
+}
+
+func BenchmarkLayouts(b *testing.B) {
+	keys := make([]string, 10000)
+	for i := range keys {
+		keys[i] = makeTenantKey(i * 7919 % len(keys))
+	}
+
+	for _, name := range []string{"default", "compressed"} {
+		opts := layouts[name]
+		build := func() trie.Trie[string, int] {
+			tr := trie.New[string, int](opts...)
+			for i, k := range keys {
+				tr = tr.Put(k, i)
+			}
+			return tr
+		}
+
+		b.Run(name+"/Put", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				build()
+			}
+		})
+
+		b.Run(name+"/Get", func(b *testing.B) {
+			tr := build()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tr.Get(keys[i%len(keys)])
+			}
+			b.ReportMetric(float64(tr.Stats().MaxDepth), "depth")
+			b.ReportMetric(float64(tr.Stats().HeapBytes), "heap-bytes")
+		})
+	}
+}
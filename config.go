@@ -0,0 +1,124 @@
+package trie
+
+import (
+	"slices"
+
+	"github.com/caravan/go-immutable-trie/key"
+	"github.com/caravan/go-immutable-trie/nibble"
+)
+
+type (
+	// Option configures the internal layout of a new Trie
+	Option func(*config)
+
+	config struct {
+		nibbles  nibble.Strategy
+		compress bool
+		score    any
+	}
+)
+
+// WithStrategy returns an Option that selects how many bits of each Key are
+// consumed at every level of the Trie. The width of each bucket array
+// follows the Strategy. By default, Keys are consumed 4 bits at a time.
+// The Strategy must be one of those defined by the nibble package
+func WithStrategy(s nibble.Strategy) Option {
+	if !s.IsValid() {
+		panic("programmer error: unknown Strategy")
+	}
+	return func(c *config) {
+		c.nibbles = s
+	}
+}
+
+// WithPathCompression returns an Option that enables path compression.
+// Rather than branching on the next unit of each Key at every level, a
+// compressed node skips the run of units that all of its Keys share. This
+// keeps Tries with long common prefixes shallow
+func WithPathCompression() Option {
+	return func(c *config) {
+		c.compress = true
+	}
+}
+
+// withoutScore returns the provided Options followed by one that discards
+// any score function, for a Trie whose Pairs it wasn't written for
+func withoutScore(opts []Option) []Option {
+	return append(slices.Clip(opts), func(c *config) {
+		c.score = nil
+	})
+}
+
+// WithScore returns an Option that maintains, for every subtree of the
+// Trie, the highest score that the provided function assigns to any of its
+// Pairs. Complete uses these annotations to find the best completions
+// without visiting every Key that has the prefix. The function is called
+// on every write, so it should be cheap, and it must never return NaN. Its
+// Key and Value types must match those of the Trie it configures, or New
+// panics. A Trie derived by MapValues with another Value type isn't
+// annotated, and neither are the Tries that back a Set, a MultiMap, or the
+// Values of a BiMap
+func WithScore[Key key.Keyable, Value any](
+	score func(Key, Value) float64,
+) Option {
+	return func(c *config) {
+		c.score = score
+	}
+}
+
+// makeConfig applies Options to the configuration of a Trie with the
+// provided Key and Value types. A WithScore function for any other types
+// could never be applied, so it's rejected
+func makeConfig[Key key.Keyable, Value any](opts []Option) *config {
+	if len(opts) == 0 {
+		return nil
+	}
+	res := &config{}
+	for _, o := range opts {
+		o(res)
+	}
+	if _, ok := scorer[Key, Value](res); res.score != nil && !ok {
+		panic("programmer error: score function doesn't match the Trie's types")
+	}
+	return res
+}
+
+func (c *config) strategy() nibble.Strategy {
+	if c == nil {
+		return nibble.Default
+	}
+	return c.nibbles
+}
+
+func (c *config) compressed() bool {
+	return c != nil && c.compress
+}
+
+// equal reports whether two configurations lay out a Trie in the same
+// way, so that nodes built with one can be adopted by a Trie built with
+// the other. Score functions can't be compared, so a configuration that
+// has one is only equal to itself
+func (c *config) equal(other *config) bool {
+	if c == other {
+		return true
+	}
+	return c.strategy() == other.strategy() &&
+		c.compressed() == other.compressed() &&
+		!c.scored() && !other.scored()
+}
+
+func (c *config) scored() bool {
+	return c != nil && c.score != nil
+}
+
+// scorer returns the score function provided by WithScore, if it applies
+// to Pairs of this Key and Value type
+func scorer[Key key.Keyable, Value any](
+	c *config,
+) (func(Key, Value) float64, bool) {
+	if c == nil {
+		return nil, false
+	}
+	res, ok := c.score.(func(Key, Value) float64)
+	return res, ok
+}
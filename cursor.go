@@ -0,0 +1,107 @@
+package trie
+
+import (
+	"encoding/base64"
+	"errors"
+
+	"github.com/caravan/go-immutable-trie/key"
+)
+
+type (
+	// Cursor marks the last Key produced by a page of Query results, along
+	// with the direction of that Query, so that it can be resumed later
+	Cursor[Key key.Keyable] interface {
+		Last() Key
+		IsDescending() bool
+		Token() string
+	}
+
+	cursor[Key key.Keyable] struct {
+		last       Key
+		descending bool
+	}
+)
+
+const (
+	ascendingCursor byte = iota
+	descendingCursor
+)
+
+// ErrInvalidCursor is returned when a Cursor token can't be decoded
+var ErrInvalidCursor = errors.New("trie: invalid cursor token")
+
+// ParseCursor decodes a Cursor from a token produced by Cursor.Token
+func ParseCursor[Key key.Keyable](token string) (Cursor[Key], error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(b) == 0 || b[0] > descendingCursor {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor[Key]{
+		last:       Key(b[1:]),
+		descending: b[0] == descendingCursor,
+	}, nil
+}
+
+func (c *cursor[Key]) Last() Key {
+	return c.last
+}
+
+func (c *cursor[_]) IsDescending() bool {
+	return c.descending
+}
+
+func (c *cursor[_]) Token() string {
+	b := make([]byte, 0, len(c.last)+1)
+	if c.descending {
+		b = append(b, descendingCursor)
+	} else {
+		b = append(b, ascendingCursor)
+	}
+	b = append(b, c.last...)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (i *iterator[Key, Value]) Resume(token string) (Query[Key, Value], error) {
+	c, err := ParseCursor[Key](token)
+	if err != nil {
+		return nil, err
+	}
+	res := i.mutate(func(i *iterator[Key, Value]) {
+		i.descending = c.IsDescending()
+	})
+	last := c.Last()
+	q := res.From(last)
+	if p, rest, ok := q.Next(); ok && key.EqualTo[Key](p.Key(), last) {
+		return rest, nil
+	}
+	return q, nil
+}
+
+func (empty[Key, Value]) Resume(token string) (Query[Key, Value], error) {
+	if _, err := ParseCursor[Key](token); err != nil {
+		return nil, err
+	}
+	return decoratedEmpty[Key, Value](), nil
+}
+
+func (d *decorated[Key, Value]) Page(
+	limit int,
+) ([]Pair[Key, Value], Cursor[Key]) {
+	var res []Pair[Key, Value]
+	var q Query[Key, Value] = d
+	for len(res) < limit {
+		p, rest, ok := q.Next()
+		if !ok {
+			return res, nil
+		}
+		res = append(res, p)
+		q = rest
+	}
+	if _, _, ok := q.Next(); ok && len(res) > 0 {
+		return res, &cursor[Key]{
+			last:       res[len(res)-1].Key(),
+			descending: d.isDescending(),
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,124 @@
+package trie_test
+
+import (
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/stretchr/testify/assert"
+)
+
+func collectPages(
+	t *testing.T, tr trie.Trie[string, int], first trie.Query[string, int],
+) []string {
+	as := assert.New(t)
+	var res []string
+	q := first
+	for {
+		page, c := q.Page(3)
+		for _, p := range page {
+			res = append(res, p.Key())
+		}
+		if c == nil {
+			return res
+		}
+		as.Equal(page[len(page)-1].Key(), c.Last())
+
+		var err error
+		q, err = tr.Select().Resume(c.Token())
+		as.Nil(err)
+	}
+}
+
+func TestCursorPagination(t *testing.T) {
+	as := assert.New(t)
+
+	tr := makeTestTrie()
+	as.Equal(tr.Select().All().Keys(), collectPages(t, tr, tr.Select().All()))
+	as.Equal(
+		tr.Select().Descending().All().Keys(),
+		collectPages(t, tr, tr.Select().Descending().All()),
+	)
+
+	page, c := tr.Select().All().Page(len(testMap))
+	as.Equal(len(testMap), len(page))
+	as.Nil(c)
+
+	page, c = tr.Select().All().Page(0)
+	as.Equal(0, len(page))
+	as.Nil(c)
+}
+
+func TestCursorDirection(t *testing.T) {
+	as := assert.New(t)
+
+	tr := makeTestTrie()
+	_, c := tr.Select().All().Where(func(k string, _ int) bool {
+		return k[0] == 'h'
+	}).Page(1)
+	as.Equal("hear", c.Last())
+	as.False(c.IsDescending())
+
+	_, c = tr.Select().Descending().All().Take(4).Page(2)
+	as.Equal("today", c.Last())
+	as.True(c.IsDescending())
+
+	q, err := tr.Select().Resume(c.Token())
+	as.Nil(err)
+	testResults(t, q.Take(2), []testEntry{
+		{"to", 64},
+		{"there", 2},
+	})
+}
+
+func TestCursorResumeAfterChange(t *testing.T) {
+	as := assert.New(t)
+
+	t1 := makeTestTrie()
+	_, c := t1.Select().All().Page(4)
+	as.Equal("curious", c.Last())
+
+	_, t2, _ := t1.Remove("curious")
+	t2 = t2.Put("cat", 3).Put("dog", 7)
+	q, err := t2.Select().Resume(c.Token())
+	as.Nil(err)
+	testResults(t, q.Take(3), []testEntry{
+		{"dog", 7},
+		{"hear", 32},
+		{"hello", 1},
+	})
+
+	t1 = makeTestTrie()
+	_, c = t1.Select().Descending().All().Page(4)
+	as.Equal("there", c.Last())
+
+	_, t3, _ := t1.Remove("there")
+	t3 = t3.Put("thence", 12)
+	q, err = t3.Select().Resume(c.Token())
+	as.Nil(err)
+	testResults(t, q.Take(2), []testEntry{
+		{"thence", 12},
+		{"how", 9},
+	})
+}
+
+func TestInvalidCursor(t *testing.T) {
+	as := assert.New(t)
+
+	tr := makeTestTrie()
+	for _, token := range []string{"", "!!!", "Ag"} {
+		q, err := tr.Select().Resume(token)
+		as.Nil(q)
+		as.Equal(trie.ErrInvalidCursor, err)
+	}
+
+	_, err := trie.ParseCursor[string]("")
+	as.Equal(trie.ErrInvalidCursor, err)
+
+	_, c := tr.Select().All().Page(1)
+	q, err := trie.New[string, int]().Select().Resume(c.Token())
+	as.Nil(err)
+	as.Equal(0, q.Count())
+
+	_, err = trie.New[string, int]().Select().Resume("!!!")
+	as.Equal(trie.ErrInvalidCursor, err)
+}
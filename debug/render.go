@@ -0,0 +1,131 @@
+// Package debug renders the internal structure of a Trie, showing its
+// nodes, their Pairs and the bucket index that leads to each child
+package debug
+
+import (
+	"fmt"
+	"strings"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/caravan/go-immutable-trie/key"
+)
+
+type (
+	// Option configures the rendering of a Trie
+	Option[Key key.Keyable, Value any] func(*renderer[Key, Value])
+
+	renderer[Key key.Keyable, Value any] struct {
+		sb     strings.Builder
+		shared map[trie.Node[Key, Value]]bool
+		nextID int
+	}
+)
+
+// SharedWith highlights the nodes that are shared structurally with
+// another version of the Trie
+func SharedWith[Key key.Keyable, Value any](
+	other trie.Trie[Key, Value],
+) Option[Key, Value] {
+	return func(r *renderer[Key, Value]) {
+		if root, ok := other.Root(); ok {
+			markNodes(root, r.shared)
+		}
+	}
+}
+
+// DOT renders a Trie as a Graphviz directed graph. Each edge is labeled
+// with the bucket index, in hexadecimal, that leads to the child
+func DOT[Key key.Keyable, Value any](
+	t trie.Trie[Key, Value], opts ...Option[Key, Value],
+) string {
+	r := makeRenderer(opts)
+	r.sb.WriteString("digraph trie {\n")
+	r.sb.WriteString("\tnode [shape=box];\n")
+	if root, ok := t.Root(); ok {
+		r.dotNode(root)
+	}
+	r.sb.WriteString("}\n")
+	return r.sb.String()
+}
+
+// ASCII renders a Trie as an indented tree, one node per line. Each child
+// is prefixed with the bucket index, in hexadecimal, that leads to it
+func ASCII[Key key.Keyable, Value any](
+	t trie.Trie[Key, Value], opts ...Option[Key, Value],
+) string {
+	r := makeRenderer(opts)
+	if root, ok := t.Root(); ok {
+		r.sb.WriteString(r.label(root))
+		r.sb.WriteByte('\n')
+		r.asciiBuckets(root, "")
+	}
+	return r.sb.String()
+}
+
+func makeRenderer[Key key.Keyable, Value any](
+	opts []Option[Key, Value],
+) *renderer[Key, Value] {
+	res := &renderer[Key, Value]{
+		shared: map[trie.Node[Key, Value]]bool{},
+	}
+	for _, o := range opts {
+		o(res)
+	}
+	return res
+}
+
+func (r *renderer[Key, Value]) dotNode(n trie.Node[Key, Value]) int {
+	id := r.nextID
+	r.nextID++
+	attrs := ""
+	if r.shared[n] {
+		attrs = ", style=filled, fillcolor=lightgrey"
+	}
+	fmt.Fprintf(&r.sb, "\tn%d [label=%q%s];\n", id, r.label(n), attrs)
+	for idx, child := range n.Buckets() {
+		cid := r.dotNode(child)
+		fmt.Fprintf(&r.sb, "\tn%d -> n%d [label=\"%x\"];\n", id, cid, idx)
+	}
+	return id
+}
+
+func (r *renderer[Key, Value]) asciiBuckets(
+	n trie.Node[Key, Value], indent string,
+) {
+	var children []trie.Node[Key, Value]
+	var indexes []uint8
+	for idx, child := range n.Buckets() {
+		children = append(children, child)
+		indexes = append(indexes, idx)
+	}
+	for i, child := range children {
+		branch, next := "├── ", "│   "
+		if i == len(children)-1 {
+			branch, next = "└── ", "    "
+		}
+		fmt.Fprintf(&r.sb, "%s%s[%x] %s\n",
+			indent, branch, indexes[i], r.label(child),
+		)
+		r.asciiBuckets(child, indent+next)
+	}
+}
+
+func (r *renderer[Key, Value]) label(n trie.Node[Key, Value]) string {
+	res := fmt.Sprintf("%q: %v", string(n.Key()), n.Value())
+	if skip := n.Skip(); skip > 0 {
+		res += fmt.Sprintf(" (skip %d)", skip)
+	}
+	if r.shared[n] {
+		res += " (shared)"
+	}
+	return res
+}
+
+func markNodes[Key key.Keyable, Value any](
+	n trie.Node[Key, Value], seen map[trie.Node[Key, Value]]bool,
+) {
+	seen[n] = true
+	for _, child := range n.Buckets() {
+		markNodes(child, seen)
+	}
+}
@@ -0,0 +1,86 @@
+package debug_test
+
+import (
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/caravan/go-immutable-trie/debug"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeTestTrie() trie.Trie[string, int] {
+	return trie.From[int](map[string]int{
+		"a":     16,
+		"are":   5,
+		"bit":   1024,
+		"hello": 1,
+		"how":   9,
+	})
+}
+
+func TestASCII(t *testing.T) {
+	as := assert.New(t)
+
+	as.Equal(""+
+		"\"a\": 16\n"+
+		"└── [6] \"are\": 5\n"+
+		"    ├── [2] \"bit\": 1024\n"+
+		"    └── [8] \"hello\": 1\n"+
+		"        └── [6] \"how\": 9\n",
+		debug.ASCII(makeTestTrie()),
+	)
+
+	as.Equal("", debug.ASCII(trie.New[string, int]()))
+}
+
+func TestASCIIShared(t *testing.T) {
+	as := assert.New(t)
+
+	t1 := makeTestTrie()
+	t2 := t1.Put("zz", 0)
+	as.Equal(""+
+		"\"a\": 16\n"+
+		"├── [6] \"are\": 5 (shared)\n"+
+		"│   ├── [2] \"bit\": 1024 (shared)\n"+
+		"│   └── [8] \"hello\": 1 (shared)\n"+
+		"│       └── [6] \"how\": 9 (shared)\n"+
+		"└── [7] \"zz\": 0\n",
+		debug.ASCII(t2, debug.SharedWith(t1)),
+	)
+}
+
+func TestASCIISkip(t *testing.T) {
+	as := assert.New(t)
+
+	tr := trie.New[string, int](trie.WithPathCompression()).
+		Put("/api/v1/a", 1).
+		Put("/api/v1/b", 2)
+	as.Equal(""+
+		"\"/api/v1/a\": 1 (skip 17)\n"+
+		"└── [2] \"/api/v1/b\": 2\n",
+		debug.ASCII(tr),
+	)
+}
+
+func TestDOT(t *testing.T) {
+	as := assert.New(t)
+
+	t1 := makeTestTrie()
+	t2, _ := t1.RemovePrefix("h")
+	as.Equal(""+
+		"digraph trie {\n"+
+		"\tnode [shape=box];\n"+
+		"\tn0 [label=\"\\\"a\\\": 16\"];\n"+
+		"\tn1 [label=\"\\\"are\\\": 5\"];\n"+
+		"\tn2 [label=\"\\\"bit\\\": 1024 (shared)\", "+
+		"style=filled, fillcolor=lightgrey];\n"+
+		"\tn1 -> n2 [label=\"2\"];\n"+
+		"\tn0 -> n1 [label=\"6\"];\n"+
+		"}\n",
+		debug.DOT(t2, debug.SharedWith(t1)),
+	)
+
+	as.Equal("digraph trie {\n\tnode [shape=box];\n}\n",
+		debug.DOT(trie.New[string, int]()),
+	)
+}
@@ -2,7 +2,9 @@ package trie
 
 import "github.com/caravan/go-immutable-trie/key"
 
-type empty[Key key.Keyable, Value any] struct{}
+type empty[Key key.Keyable, Value any] struct {
+	cfg *config
+}
 
 func (empty[_, _]) trie() {}
 
@@ -11,19 +13,20 @@ func (empty[Key, Value]) Get(Key) (Value, bool) {
 	return zero, false
 }
 
-func (empty[Key, Value]) Put(k Key, v Value) Trie[Key, Value] {
+func (e empty[Key, Value]) Put(k Key, v Value) Trie[Key, Value] {
 	return &trie[Key, Value]{
 		pair: pair[Key, Value]{k, v},
+		cfg:  e.cfg,
 	}
 }
 
-func (empty[Key, Value]) Remove(_ Key) (Value, Trie[Key, Value], bool) {
+func (e empty[Key, Value]) Remove(_ Key) (Value, Trie[Key, Value], bool) {
 	var zero Value
-	return zero, empty[Key, Value]{}, false
+	return zero, e, false
 }
 
-func (empty[Key, Value]) RemovePrefix(Key) (Trie[Key, Value], bool) {
-	return empty[Key, Value]{}, false
+func (e empty[Key, Value]) RemovePrefix(Key) (Trie[Key, Value], bool) {
+	return e, false
 }
 
 func (empty[_, _]) IsEmpty() bool {
@@ -34,8 +37,8 @@ func (empty[_, _]) Count() int {
 	return 0
 }
 
-func (empty[Key, Value]) Split() (Pair[Key, Value], Trie[Key, Value], bool) {
-	return nil, empty[Key, Value]{}, false
+func (e empty[Key, Value]) Split() (Pair[Key, Value], Trie[Key, Value], bool) {
+	return nil, e, false
 }
 
 func (e empty[Key, Value]) First() Pair[Key, Value] {
@@ -72,6 +75,10 @@ func (e empty[Key, Value]) Next() (Pair[Key, Value], Query[Key, Value], bool) {
 	return nil, nil, false
 }
 
+func (e empty[Key, Value]) config() *config {
+	return e.cfg
+}
+
 func decoratedEmpty[Key key.Keyable, Value any]() Query[Key, Value] {
 	return decorate[Key, Value](empty[Key, Value]{})
 }
@@ -0,0 +1,79 @@
+package trie
+
+import "github.com/caravan/go-immutable-trie/key"
+
+// Corruptions break the internal structure of a Trie in various ways, so
+// that Validate can be tested. Each expects the Trie's root to have at
+// least two children
+var Corruptions = map[string]func(*trie[string, int]){
+	"unordered": func(t *trie[string, int]) {
+		child := t.buckets.children[0]
+		t.pair, child.pair = child.pair, t.pair
+	},
+	"misplaced": func(t *trie[string, int]) {
+		t.buckets.children[0], t.buckets.children[1] =
+			t.buckets.children[1], t.buckets.children[0]
+	},
+	"reached twice": func(t *trie[string, int]) {
+		t.buckets.children[1] = t.buckets.children[0]
+	},
+	"bitmap": func(t *trie[string, int]) {
+		t.buckets.children = t.buckets.children[1:]
+	},
+	"empty bucket": func(t *trie[string, int]) {
+		t.buckets.children[0] = nil
+	},
+	"skip": func(t *trie[string, int]) {
+		t.buckets.skip = int32(len(t.key)*2 + 1)
+	},
+	"config": func(t *trie[string, int]) {
+		t.buckets.children[0].cfg = &config{compress: true}
+	},
+}
+
+// Corrupt applies a corruption to a deep copy of the provided Trie
+func Corrupt(t Trie[string, int], name string) Trie[string, int] {
+	res := deepCopy(t.(*trie[string, int]))
+	Corruptions[name](res)
+	return res
+}
+
+func deepCopy[Key key.Keyable, Value any](t *trie[Key, Value]) *trie[Key, Value] {
+	res := *t
+	if t.buckets != nil {
+		b := *t.buckets
+		b.children = make([]*trie[Key, Value], len(t.buckets.children))
+		res.buckets = &b
+		for i, bucket := range t.buckets.children {
+			res.buckets.children[i] = deepCopy(bucket)
+		}
+	}
+	return &res
+}
+
+// Extras counts the buckets of a Trie that had to allocate a bucketsExtra
+func Extras[Key key.Keyable, Value any](t Trie[Key, Value]) int {
+	n, ok := t.(*trie[Key, Value])
+	if !ok {
+		return 0
+	}
+	res := 0
+	if n.buckets != nil && n.buckets.extra != nil {
+		res++
+	}
+	for _, bucket := range n.buckets.nodes() {
+		res += Extras[Key, Value](bucket)
+	}
+	return res
+}
+
+// Partitions returns the number of partitions that FromParallel builds
+// concurrently for the provided Keys and workers
+func Partitions(keys []string, workers int) int {
+	root := &trie[string, int]{}
+	all := &partition[string, int]{}
+	for i, k := range keys {
+		all.pairs = append(all.pairs, pair[string, int]{k, i})
+	}
+	return len(all.split(root, workers*tasksPerWorker))
+}
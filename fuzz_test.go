@@ -0,0 +1,96 @@
+package trie_test
+
+import (
+	"math/rand"
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/caravan/go-immutable-trie/trietest"
+)
+
+// regressions are minimized sequences that once exposed bugs
+var regressions = []trietest.Ops{
+	{
+		// From skipped to a longer Key when the sought Key was absent
+		{Kind: trietest.Put, Key: "a", Value: 0},
+		{Kind: trietest.Put, Key: "ab", Value: 1},
+		{Kind: trietest.From, Key: "b"},
+	},
+	{
+		// Remove wrote into buckets shared with the previous version
+		{Kind: trietest.Put, Key: "a", Value: 0},
+		{Kind: trietest.Put, Key: "b", Value: 1},
+		{Kind: trietest.Put, Key: "ba", Value: 2},
+		{Kind: trietest.Put, Key: "bb", Value: 3},
+		{Kind: trietest.Remove, Key: "ba"},
+	},
+	{
+		// Branching on a Key exhausted within a compressed run panicked
+		{Kind: trietest.Put, Key: "/api/v1/", Value: 0},
+		{Kind: trietest.Put, Key: "/api/v1/tenants", Value: 1},
+		{Kind: trietest.Put, Key: "/api", Value: 2},
+		{Kind: trietest.Descending, Key: "/api/v1/t"},
+	},
+}
+
+func runOps(t *testing.T, ops trietest.Ops) {
+	t.Helper()
+	for name, opts := range layouts {
+		fails := func(ops trietest.Ops) bool {
+			return trietest.Run(trie.New[string, int](opts...), ops) != nil
+		}
+		err := trietest.Run(trie.New[string, int](opts...), ops)
+		if err != nil {
+			t.Fatalf("%s layout: %v\nminimized sequence:\n%#v",
+				name, err, trietest.Minimize(ops, fails),
+			)
+		}
+	}
+}
+
+func TestRegressions(t *testing.T) {
+	for _, ops := range regressions {
+		runOps(t, ops)
+	}
+}
+
+func TestModelSequences(t *testing.T) {
+	rng := rand.New(rand.NewSource(43))
+	for _, alphabet := range []string{"ab", "abc/", "\x00\x01\xff"} {
+		for i := 0; i < 25; i++ {
+			runOps(t, trietest.Generate(rng, 200, alphabet, 6))
+		}
+	}
+}
+
+func FuzzOps(f *testing.F) {
+	for _, ops := range regressions {
+		f.Add(trietest.Encode(ops))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		runOps(t, trietest.Decode(data))
+	})
+}
+
+func FuzzKeys(f *testing.F) {
+	f.Add([]byte("hello\x00help\x00he"), "hel")
+	f.Add([]byte("\x00\xff\x00\xfe"), "")
+	f.Fuzz(func(t *testing.T, keys []byte, probe string) {
+		var ops trietest.Ops
+		start := 0
+		for i, b := range keys {
+			if b == 0 {
+				ops = append(ops, trietest.Op{
+					Kind: trietest.Put, Key: string(keys[start:i]), Value: i,
+				})
+				start = i + 1
+			}
+		}
+		ops = append(ops,
+			trietest.Op{Kind: trietest.From, Key: probe},
+			trietest.Op{Kind: trietest.Descending, Key: probe},
+			trietest.Op{Kind: trietest.RemovePrefix, Key: probe},
+		)
+		runOps(t, ops)
+	})
+}
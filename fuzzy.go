@@ -0,0 +1,127 @@
+package trie
+
+import (
+	"iter"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/caravan/go-immutable-trie/key"
+)
+
+// levenshtein is an automaton that accepts input within an edit distance
+// of a query. Each of its states is a row of edit distances between the
+// query's prefixes and the input read so far
+type levenshtein struct {
+	query   []rune
+	maxDist int
+	runes   bool
+}
+
+// Fuzzy iterates, in Key order, over the Pairs whose Keys are within the
+// provided Levenshtein distance of the query, counting edits by byte. The
+// Trie is walked with a Levenshtein automaton, and subtrees whose shared
+// path already exceeds the distance are skipped
+func (t *trie[Key, Value]) Fuzzy(
+	query Key, maxDist int,
+) iter.Seq2[Key, Value] {
+	return t.fuzzy(newLevenshtein(query, maxDist, false))
+}
+
+// FuzzyRunes is like Fuzzy, but counts edits by UTF-8 encoded rune
+func (t *trie[Key, Value]) FuzzyRunes(
+	query Key, maxDist int,
+) iter.Seq2[Key, Value] {
+	return t.fuzzy(newLevenshtein(query, maxDist, true))
+}
+
+func (t *trie[Key, Value]) fuzzy(l *levenshtein) iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		if l.maxDist >= 0 {
+			p := position[[]int]{state: l.start()}
+			t.walkFuzzy(l, p, 0, yield)
+		}
+	}
+}
+
+func (t *trie[Key, Value]) walkFuzzy(
+	l *levenshtein, p position[[]int], depth int, yield func(Key, Value) bool,
+) bool {
+	p, depth, ok := enter(t, l, p, depth)
+	if !ok {
+		return true
+	}
+	if acceptsKey(l, p, []byte(t.key)) && !yield(t.key, t.value) {
+		return false
+	}
+	for _, bucket := range t.buckets.nodes() {
+		if !bucket.walkFuzzy(l, p, depth, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+func newLevenshtein[Key key.Keyable](
+	query Key, maxDist int, runes bool,
+) *levenshtein {
+	res := &levenshtein{maxDist: maxDist, runes: runes}
+	for q := []byte(query); len(q) > 0; {
+		r, size := rune(q[0]), 1
+		if runes {
+			// a truncated rune decodes as utf8.RuneError, one byte at a
+			// time, just as the trailing bytes of a Key do
+			r, size = utf8.DecodeRune(q)
+		}
+		res.query = append(res.query, r)
+		q = q[size:]
+	}
+	return res
+}
+
+func (l *levenshtein) start() []int {
+	res := make([]int, len(l.query)+1)
+	for i := range res {
+		res[i] = i
+	}
+	return res
+}
+
+func (l *levenshtein) step(row []int, r rune) []int {
+	res := make([]int, len(row))
+	res[0] = row[0] + 1
+	for i, q := range l.query {
+		cost := 1
+		if q == r {
+			cost = 0
+		}
+		res[i+1] = min(res[i]+1, row[i+1]+1, row[i]+cost)
+	}
+	return res
+}
+
+func (l *levenshtein) viable(row []int) bool {
+	return slices.Min(row) <= l.maxDist
+}
+
+func (l *levenshtein) accepts(row []int) bool {
+	return row[len(l.query)] <= l.maxDist
+}
+
+func (l *levenshtein) decode(b []byte) (rune, int, bool) {
+	if !l.runes {
+		return rune(b[0]), 1, true
+	}
+	if !utf8.FullRune(b) {
+		return 0, 0, false
+	}
+	r, size := utf8.DecodeRune(b)
+	return r, size, true
+}
+
+func (empty[Key, Value]) Fuzzy(Key, int) iter.Seq2[Key, Value] {
+	return func(func(Key, Value) bool) {}
+}
+
+func (empty[Key, Value]) FuzzyRunes(Key, int) iter.Seq2[Key, Value] {
+	return func(func(Key, Value) bool) {}
+}
@@ -0,0 +1,137 @@
+package trie_test
+
+import (
+	"math/rand"
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/stretchr/testify/assert"
+)
+
+// distance computes the Levenshtein distance between two sequences
+func distance[T comparable](l, r []T) int {
+	row := make([]int, len(r)+1)
+	for i := range row {
+		row[i] = i
+	}
+	for i := range l {
+		prev := row[0]
+		row[0] = i + 1
+		for j := range r {
+			cost := 1
+			if l[i] == r[j] {
+				cost = 0
+			}
+			prev, row[j+1] = row[j+1], min(row[j+1]+1, row[j]+1, prev+cost)
+		}
+	}
+	return row[len(r)]
+}
+
+func fuzzyKeys(seq func(func(string, int) bool)) []string {
+	res := []string{}
+	for k := range seq {
+		res = append(res, k)
+	}
+	return res
+}
+
+func TestFuzzy(t *testing.T) {
+	as := assert.New(t)
+
+	tr := trie.From(map[string]int{
+		"apple":  1,
+		"apply":  2,
+		"ample":  3,
+		"maple":  4,
+		"orange": 5,
+		"café":   6,
+		"cafe":   7,
+		"cave":   8,
+	})
+	as.Equal([]string{"apple"}, fuzzyKeys(tr.Fuzzy("apple", 0)))
+	as.Equal([]string{"ample", "apple", "apply"},
+		fuzzyKeys(tr.Fuzzy("apple", 1)))
+	as.Equal([]string{"ample", "apple", "apply", "maple"},
+		fuzzyKeys(tr.Fuzzy("apple", 2)))
+	as.Equal([]string{"apple", "apply"}, fuzzyKeys(tr.Fuzzy("appl", 1)))
+	as.Empty(fuzzyKeys(tr.Fuzzy("apple", -1)))
+
+	// é is two bytes, but a single rune
+	as.Equal([]string{"cafe", "cave"}, fuzzyKeys(tr.Fuzzy("cafe", 1)))
+	as.Equal([]string{"cafe", "café", "cave"},
+		fuzzyKeys(tr.FuzzyRunes("cafe", 1)))
+	as.Equal([]string{"cafe", "café"}, fuzzyKeys(tr.FuzzyRunes("cafè", 1)))
+
+	v, ok := 0, false
+	for _, val := range tr.Fuzzy("apple", 5) {
+		v, ok = val, true
+		break
+	}
+	as.True(ok)
+	as.Equal(3, v)
+
+	as.Empty(fuzzyKeys(trie.New[string, int]().Fuzzy("apple", 3)))
+	as.Empty(fuzzyKeys(trie.New[string, int]().FuzzyRunes("apple", 3)))
+}
+
+func TestFuzzyInvalidUTF8(t *testing.T) {
+	as := assert.New(t)
+
+	// invalid and truncated runes are read as utf8.RuneError, a byte at a
+	// time, in both Keys and queries
+	tr := trie.From(map[string]int{
+		"ab":            1,
+		"ab\xe2":        2,
+		"a\xe2\x82":     3,
+		"a\xe2\x82\xac": 4,
+	})
+	as.Equal([]string{"ab"}, fuzzyKeys(tr.FuzzyRunes("ab", 0)))
+	as.Equal([]string{"ab", "ab\xe2", "a\xe2\x82\xac"},
+		fuzzyKeys(tr.FuzzyRunes("ab", 1)))
+	as.Equal([]string{"a\xe2\x82"}, fuzzyKeys(tr.FuzzyRunes("a\xe2\x82", 0)))
+	as.Equal([]string{"ab\xe2", "a\xe2\x82"},
+		fuzzyKeys(tr.FuzzyRunes("a\xe2\x82", 1)))
+	as.Equal([]string{"a\xe2\x82", "a\xe2\x82\xac"},
+		fuzzyKeys(tr.Fuzzy("a\xe2\x82", 1)))
+}
+
+func TestFuzzyRandom(t *testing.T) {
+	alphabet := []rune("abcé/")
+	randomWord := func(rng *rand.Rand) string {
+		res := make([]rune, rng.Intn(7))
+		for i := range res {
+			res[i] = alphabet[rng.Intn(len(alphabet))]
+		}
+		return string(res)
+	}
+
+	for name, opts := range layouts {
+		t.Run(name, func(t *testing.T) {
+			as := assert.New(t)
+			rng := rand.New(rand.NewSource(49))
+
+			tr := trie.New[string, int](opts...)
+			for i := 0; i < 500; i++ {
+				tr = tr.Put(randomWord(rng), i)
+			}
+			for i := 0; i < 50; i++ {
+				query := randomWord(rng)
+				maxDist := rng.Intn(3)
+				bytes, runes := []string{}, []string{}
+				tr.Select().All().ForEach(func(k string, _ int) {
+					if distance([]byte(k), []byte(query)) <= maxDist {
+						bytes = append(bytes, k)
+					}
+					if distance([]rune(k), []rune(query)) <= maxDist {
+						runes = append(runes, k)
+					}
+				})
+				as.Equal(bytes, fuzzyKeys(tr.Fuzzy(query, maxDist)),
+					"%q %d", query, maxDist)
+				as.Equal(runes, fuzzyKeys(tr.FuzzyRunes(query, maxDist)),
+					"%q %d", query, maxDist)
+			}
+		})
+	}
+}
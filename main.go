@@ -1,17 +1,62 @@
 package trie
 
-import "github.com/caravan/go-immutable-trie/key"
+import (
+	"iter"
 
-// New returns a new empty Trie instance
-func New[Key key.Keyable, Value any]() Trie[Key, Value] {
-	return empty[Key, Value]{}
+	"github.com/caravan/go-immutable-trie/key"
+)
+
+// New returns a new empty Trie instance, configured by the provided Options
+func New[Key key.Keyable, Value any](opts ...Option) Trie[Key, Value] {
+	return empty[Key, Value]{
+		cfg: makeConfig[Key, Value](opts),
+	}
 }
 
 // From builds a Trie from a map with string keys
-func From[Value any](in map[string]Value) Trie[string, Value] {
-	res := New[string, Value]()
+func From[Value any](
+	in map[string]Value, opts ...Option,
+) Trie[string, Value] {
+	res := New[string, Value](opts...)
 	for k, v := range in {
 		res = res.Put(k, v)
 	}
 	return res
 }
+
+// FromParallel builds a Trie from a sequence of Key/Value Pairs. The Pairs
+// are partitioned by the leading units of their Keys, going deeper into the
+// Keys until there are enough partitions to keep the requested number of
+// worker goroutines busy, and each partition is built concurrently. If
+// workers is less than one, GOMAXPROCS is used. When a Key appears more
+// than once, its last Value wins
+func FromParallel[Key key.Keyable, Value any](
+	seq iter.Seq2[Key, Value], workers int, opts ...Option,
+) Trie[Key, Value] {
+	root := &trie[Key, Value]{cfg: makeConfig[Key, Value](opts)}
+	all := &partition[Key, Value]{}
+	for k, v := range seq {
+		all.pairs = append(all.pairs, pair[Key, Value]{k, v})
+	}
+	parts := all.split(root, workerCount(workers)*tasksPerWorker)
+	runTasks(len(parts), workers, func(idx int) {
+		parts[idx].built = root.buildAt(parts[idx].pairs, parts[idx].depth)
+	})
+	return root.wrap(all.assemble(root))
+}
+
+// buildAt builds a subtree from Pairs whose Keys share their first depth
+// units
+func (t *trie[Key, Value]) buildAt(
+	pairs []pair[Key, Value], depth int,
+) *trie[Key, Value] {
+	if len(pairs) == 0 {
+		return nil
+	}
+	res := t.leaf(&pairs[0])
+	for i := 1; i < len(pairs); i++ {
+		p := &pairs[i]
+		res = res.put(p, skipUnits(t.nibbles(p.key), depth))
+	}
+	return res
+}
@@ -0,0 +1,276 @@
+package trie
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"github.com/caravan/go-immutable-trie/key"
+)
+
+type (
+	// glob is an automaton compiled from a wildcard pattern. Each of its
+	// states marks which of the pattern's elements could be matched next
+	glob struct {
+		elems []globElem
+	}
+
+	globElem struct {
+		kind    globKind
+		lit     rune
+		ranges  []runeRange
+		negated bool
+	}
+
+	globKind uint8
+
+	runeRange struct {
+		lo, hi rune
+	}
+
+	// matches is a Query over the Pairs whose Keys match a glob. It walks
+	// a stack of the subtrees that remain to be visited
+	matches[Key key.Keyable, Value any] struct {
+		glob   *glob
+		root   *trie[Key, Value]
+		stack  *matchFrame[Key, Value]
+		cancel *canceller
+	}
+
+	matchFrame[Key key.Keyable, Value any] struct {
+		node  *trie[Key, Value]
+		pos   position[[]bool]
+		depth int
+		next  *matchFrame[Key, Value]
+	}
+)
+
+const (
+	globLiteral globKind = iota
+	globAny
+	globStar
+	globClass
+)
+
+// ErrBadPattern is returned by Match when a pattern is malformed
+var ErrBadPattern = errors.New("trie: malformed pattern")
+
+// Match returns a Query over the Pairs whose Keys match a wildcard pattern,
+// in Key order. A '*' matches any run of runes, including none, and a '?'
+// matches any single rune. A class such as [a-z0-9] matches one rune in
+// any of its ranges, or outside of all of them if it opens with '^' or
+// '!'. A '\' matches the rune that follows it literally. Subtrees whose
+// shared path can't match the pattern are skipped
+func (t *trie[Key, Value]) Match(pattern Key) (Query[Key, Value], error) {
+	g, err := compileGlob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return (&matches[Key, Value]{
+		glob: g,
+		root: t,
+		stack: &matchFrame[Key, Value]{
+			node: t,
+			pos:  position[[]bool]{state: g.start()},
+		},
+	}).decorate(), nil
+}
+
+func (m *matches[Key, Value]) Next() (
+	Pair[Key, Value], Query[Key, Value], bool,
+) {
+	for f := m.stack; f != nil; {
+		if m.cancel != nil && m.cancel.cancelled() {
+			break
+		}
+		node, rest := f.node, f.next
+		pos, depth, ok := enter(node, m.glob, f.pos, f.depth)
+		if !ok {
+			f = rest
+			continue
+		}
+		buckets := node.buckets.nodes()
+		for i := len(buckets) - 1; i >= 0; i-- {
+			rest = &matchFrame[Key, Value]{buckets[i], pos, depth, rest}
+		}
+		if acceptsKey(m.glob, pos, []byte(node.key)) {
+			p := node.pair
+			q := &matches[Key, Value]{m.glob, m.root, rest, m.cancel}
+			return &p, q.decorate(), true
+		}
+		f = rest
+	}
+	return nil, decoratedEmpty[Key, Value](), false
+}
+
+func (m *matches[Key, Value]) config() *config {
+	return m.root.cfg
+}
+
+func (m *matches[Key, Value]) decorate() Query[Key, Value] {
+	return decorate[Key, Value](m)
+}
+
+func (m *matches[Key, Value]) withCanceller(c *canceller) Query[Key, Value] {
+	return (&matches[Key, Value]{m.glob, m.root, m.stack, c}).decorate()
+}
+
+func (m *matches[Key, Value]) reverse() Query[Key, Value] {
+	p, _, ok := m.Next()
+	if !ok {
+		return decoratedEmpty[Key, Value]()
+	}
+	return m.root.Select().Descending().From(p.Key()).Where(
+		func(k Key, _ Value) bool {
+			return acceptsKey(m.glob, position[[]bool]{
+				state: m.glob.start(),
+			}, []byte(k))
+		},
+	)
+}
+
+func compileGlob[Key key.Keyable](pattern Key) (*glob, error) {
+	res := &glob{}
+	for p := []byte(pattern); len(p) > 0; {
+		r, size := utf8.DecodeRune(p)
+		p = p[size:]
+		switch r {
+		case '*':
+			res.elems = append(res.elems, globElem{kind: globStar})
+		case '?':
+			res.elems = append(res.elems, globElem{kind: globAny})
+		case '[':
+			elem, rest, err := compileClass(p)
+			if err != nil {
+				return nil, err
+			}
+			res.elems = append(res.elems, elem)
+			p = rest
+		case '\\':
+			if len(p) == 0 {
+				return nil, ErrBadPattern
+			}
+			r, size = utf8.DecodeRune(p)
+			p = p[size:]
+			fallthrough
+		default:
+			res.elems = append(res.elems, globElem{kind: globLiteral, lit: r})
+		}
+	}
+	return res, nil
+}
+
+// compileClass parses the body of a character class, up to and including
+// its closing bracket
+func compileClass(p []byte) (globElem, []byte, error) {
+	res := globElem{kind: globClass}
+	if len(p) > 0 && (p[0] == '^' || p[0] == '!') {
+		res.negated = true
+		p = p[1:]
+	}
+	for {
+		if len(p) == 0 {
+			return res, nil, ErrBadPattern
+		}
+		if p[0] == ']' && len(res.ranges) > 0 {
+			return res, p[1:], nil
+		}
+		lo, rest, ok := classRune(p)
+		if !ok {
+			return res, nil, ErrBadPattern
+		}
+		hi := lo
+		if len(rest) > 1 && rest[0] == '-' && rest[1] != ']' {
+			if hi, rest, ok = classRune(rest[1:]); !ok || hi < lo {
+				return res, nil, ErrBadPattern
+			}
+		}
+		res.ranges = append(res.ranges, runeRange{lo, hi})
+		p = rest
+	}
+}
+
+func classRune(p []byte) (rune, []byte, bool) {
+	if p[0] == '\\' {
+		p = p[1:]
+	}
+	if len(p) == 0 {
+		return 0, nil, false
+	}
+	r, size := utf8.DecodeRune(p)
+	return r, p[size:], true
+}
+
+func (g *glob) start() []bool {
+	res := make([]bool, len(g.elems)+1)
+	res[0] = true
+	return g.closure(res)
+}
+
+func (g *glob) step(state []bool, r rune) []bool {
+	res := make([]bool, len(state))
+	for i, elem := range g.elems {
+		switch {
+		case !state[i]:
+		case elem.kind == globStar:
+			res[i] = true
+		case elem.matches(r):
+			res[i+1] = true
+		}
+	}
+	return g.closure(res)
+}
+
+// closure marks the elements that follow an active '*' as active, as it
+// may match nothing
+func (g *glob) closure(state []bool) []bool {
+	for i, elem := range g.elems {
+		if state[i] && elem.kind == globStar {
+			state[i+1] = true
+		}
+	}
+	return state
+}
+
+func (g *glob) viable(state []bool) bool {
+	for _, active := range state {
+		if active {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *glob) accepts(state []bool) bool {
+	return state[len(g.elems)]
+}
+
+func (g *glob) decode(b []byte) (rune, int, bool) {
+	if !utf8.FullRune(b) {
+		return 0, 0, false
+	}
+	r, size := utf8.DecodeRune(b)
+	return r, size, true
+}
+
+func (e *globElem) matches(r rune) bool {
+	switch e.kind {
+	case globLiteral:
+		return r == e.lit
+	case globAny:
+		return true
+	default:
+		for _, rng := range e.ranges {
+			if rng.lo <= r && r <= rng.hi {
+				return !e.negated
+			}
+		}
+		return e.negated
+	}
+}
+
+func (e empty[Key, Value]) Match(pattern Key) (Query[Key, Value], error) {
+	if _, err := compileGlob(pattern); err != nil {
+		return nil, err
+	}
+	return decoratedEmpty[Key, Value](), nil
+}
@@ -0,0 +1,170 @@
+package trie_test
+
+import (
+	"math/rand"
+	"regexp"
+	"strings"
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/stretchr/testify/assert"
+)
+
+func matchKeys(
+	as *assert.Assertions, tr trie.Trie[string, int], pattern string,
+) []string {
+	q, err := tr.Match(pattern)
+	as.Nil(err)
+	res := []string{}
+	q.ForEach(func(k string, _ int) {
+		res = append(res, k)
+	})
+	return res
+}
+
+// globRegexp translates a pattern into an equivalent regular expression
+func globRegexp(pattern string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString(`(?s)^`)
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			sb.WriteString(`.*`)
+		case '?':
+			sb.WriteString(`.`)
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	sb.WriteString(`$`)
+	return regexp.MustCompile(sb.String())
+}
+
+func TestMatch(t *testing.T) {
+	as := assert.New(t)
+
+	tr := trie.From(map[string]int{
+		"tenant-a/config/en.json":   1,
+		"tenant-a/config/fr.json":   2,
+		"tenant-a/config/main.json": 3,
+		"tenant-b/config/de.json":   4,
+		"tenant-b/data/x.json":      5,
+		"tenant-é/config/ja.json":   6,
+		"other/config/en.json":      7,
+		"a*b":                       8,
+		"a-b":                       9,
+	})
+
+	as.Equal([]string{
+		"tenant-a/config/en.json",
+		"tenant-a/config/fr.json",
+		"tenant-b/config/de.json",
+		"tenant-é/config/ja.json",
+	}, matchKeys(as, tr, "tenant-*/config/??.json"))
+	as.Equal([]string{"tenant-é/config/ja.json"},
+		matchKeys(as, tr, "tenant-?/config/ja.json"))
+	as.Equal([]string{
+		"tenant-a/config/en.json",
+		"tenant-a/config/fr.json",
+		"tenant-a/config/main.json",
+	}, matchKeys(as, tr, "tenant-[a]/*"))
+	as.Equal([]string{"tenant-b/config/de.json", "tenant-b/data/x.json"},
+		matchKeys(as, tr, "tenant-[b-d]/*"))
+	as.Equal([]string{"tenant-b/config/de.json", "tenant-é/config/ja.json"},
+		matchKeys(as, tr, "tenant-[^a]/config/*"))
+	as.Equal([]string{"tenant-b/config/de.json", "tenant-é/config/ja.json"},
+		matchKeys(as, tr, "tenant-[!a]/config/*"))
+	as.Equal([]string{"a*b"}, matchKeys(as, tr, `a\*b`))
+	as.Equal([]string{"a*b", "a-b"}, matchKeys(as, tr, `a[*\-]b`))
+	as.Equal([]string{"a-b"}, matchKeys(as, tr, `a[a-]b`))
+	as.Equal([]string{"other/config/en.json"},
+		matchKeys(as, tr, "other/config/en.json"))
+	as.Empty(matchKeys(as, tr, "other"))
+	as.Empty(matchKeys(as, tr, ""))
+	as.Len(matchKeys(as, tr, "*"), tr.Count())
+
+	q, err := tr.Match("*/config/en.json")
+	as.Nil(err)
+	as.Equal(2, q.Count())
+	as.Equal("tenant-a/config/en.json", q.Take(2).Last().Key())
+	_, rest, _ := q.Next()
+	as.Equal("other/config/en.json", q.First().Key())
+	as.Equal("tenant-a/config/en.json", rest.First().Key())
+
+	q, _ = tr.Match("tenant-*/config/*")
+	_, rest, _ = q.Next()
+	_, rest, _ = rest.Next()
+	as.Equal([]string{
+		"tenant-a/config/main.json",
+		"tenant-a/config/fr.json",
+		"tenant-a/config/en.json",
+	}, rest.Reverse().Keys())
+
+	q, err = trie.New[string, int]().Match("a*")
+	as.Nil(err)
+	as.Equal(0, q.Count())
+}
+
+func TestMatchInvalidUTF8(t *testing.T) {
+	as := assert.New(t)
+
+	tr := trie.From(map[string]int{
+		"ab":            1,
+		"ab\xe2":        2,
+		"a\xe2\x82\xac": 3,
+	})
+	as.Equal([]string{"ab"}, matchKeys(as, tr, "ab"))
+	as.Equal([]string{"ab\xe2"}, matchKeys(as, tr, "ab?"))
+	as.Equal([]string{"ab", "a\xe2\x82\xac"}, matchKeys(as, tr, "a?"))
+	as.Equal([]string{"ab", "ab\xe2", "a\xe2\x82\xac"}, matchKeys(as, tr, "a*"))
+
+	q, err := tr.Match("ab")
+	as.Nil(err)
+	as.Equal([]string{"ab"}, q.Reverse().Keys())
+}
+
+func TestMatchBadPattern(t *testing.T) {
+	as := assert.New(t)
+
+	tr := trie.From(map[string]int{"a": 1})
+	for _, pattern := range []string{
+		"[", "[a", "[^", "a[]", "[z-a]", "[a-", `\`, `[\`,
+	} {
+		_, err := tr.Match(pattern)
+		as.ErrorIs(err, trie.ErrBadPattern, pattern)
+		_, err = trie.New[string, int]().Match(pattern)
+		as.ErrorIs(err, trie.ErrBadPattern, pattern)
+	}
+}
+
+func TestMatchRandom(t *testing.T) {
+	for name, opts := range layouts {
+		t.Run(name, func(t *testing.T) {
+			as := assert.New(t)
+			rng := rand.New(rand.NewSource(50))
+
+			tr := trie.New[string, int](opts...)
+			for i := 0; i < 1000; i++ {
+				tr = tr.Put(randomKey(rng), i)
+			}
+			for i := 0; i < 100; i++ {
+				var sb strings.Builder
+				if rng.Intn(2) == 0 {
+					sb.WriteString("/api/v?/")
+				}
+				for j := rng.Intn(8); j > 0; j-- {
+					sb.WriteByte("abc/*?"[rng.Intn(6)])
+				}
+				pattern := sb.String()
+				re := globRegexp(pattern)
+				expected := []string{}
+				tr.Select().All().ForEach(func(k string, _ int) {
+					if re.MatchString(k) {
+						expected = append(expected, k)
+					}
+				})
+				as.Equal(expected, matchKeys(as, tr, pattern), pattern)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package trie
+
+import (
+	"encoding/binary"
+	"iter"
+
+	"github.com/caravan/go-immutable-trie/key"
+)
+
+type (
+	// MultiMap is an immutable, ordered mapping of Keys to sets of Values.
+	// Adding a Value to a Key only copies the path to that Key's set and
+	// the path to the Value within it, rather than the Values that the Key
+	// already holds. Each set is kept in the order of its Values' encodings
+	MultiMap[Key key.Keyable, Value any] interface {
+		Contains(Key, Value) bool
+		GetAll(Key) iter.Seq[Value]
+		Count() int
+		CountKeys() int
+		CountValues(Key) int
+		IsEmpty() bool
+		Add(Key, Value) MultiMap[Key, Value]
+		RemoveValue(Key, Value) MultiMap[Key, Value]
+		RemoveKey(Key) MultiMap[Key, Value]
+		Keys() iter.Seq[Key]
+		All() iter.Seq2[Key, Value]
+		Descending() iter.Seq2[Key, Value]
+	}
+
+	// Codec encodes the Values of a MultiMap as the strings that its sets
+	// hold. Two Values are the same Value if their encodings are equal, and
+	// Decode must reverse Encode
+	Codec[Value any] struct {
+		Encode func(Value) string
+		Decode func(string) Value
+	}
+
+	multiMap[Key key.Keyable, Value any] struct {
+		trie  Trie[Key, Set[string]]
+		codec *Codec[Value]
+		count int
+	}
+
+	integer interface {
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+			~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+	}
+)
+
+// NewMultiMap returns a new empty MultiMap of Keyable Values, with its Keys
+// configured by the provided Options as NewMultiMapWith does. Values of other types, such as
+// integer IDs, are held by a MultiMap from NewMultiMapWith
+func NewMultiMap[Key key.Keyable, Value key.Keyable](
+	opts ...Option,
+) MultiMap[Key, Value] {
+	return NewMultiMapWith[Key](Codec[Value]{
+		Encode: func(v Value) string { return string(v) },
+		Decode: func(s string) Value { return Value(s) },
+	}, opts...)
+}
+
+// NewMultiMapWith returns a new empty MultiMap whose Values are encoded by
+// the provided Codec, with its Keys configured by the provided Options. A
+// MultiMap is never completed, so a WithScore Option is ignored
+func NewMultiMapWith[Key key.Keyable, Value any](
+	c Codec[Value], opts ...Option,
+) MultiMap[Key, Value] {
+	return &multiMap[Key, Value]{
+		trie:  New[Key, Set[string]](withoutScore(opts)...),
+		codec: &c,
+	}
+}
+
+// IntegerCodec returns a Codec that encodes integers big-endian, with the
+// sign bit of signed integers flipped, so that their sets are kept in
+// numeric order
+func IntegerCodec[Value integer]() Codec[Value] {
+	var zero Value
+	var flip uint64
+	if ^zero < 0 {
+		flip = 1 << 63
+	}
+	return Codec[Value]{
+		Encode: func(v Value) string {
+			return string(binary.BigEndian.AppendUint64(nil, uint64(v)^flip))
+		},
+		Decode: func(s string) Value {
+			return Value(binary.BigEndian.Uint64([]byte(s)) ^ flip)
+		},
+	}
+}
+
+func (m *multiMap[Key, Value]) Contains(k Key, v Value) bool {
+	if s, ok := m.trie.Get(k); ok {
+		return s.Contains(m.codec.Encode(v))
+	}
+	return false
+}
+
+// GetAll iterates over the Values held by a Key, in the order of their
+// encodings. There are none if the Key isn't present
+func (m *multiMap[Key, Value]) GetAll(k Key) iter.Seq[Value] {
+	return func(yield func(Value) bool) {
+		if s, ok := m.trie.Get(k); ok {
+			for v := range s.All() {
+				if !yield(m.codec.Decode(v)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Count returns the number of Key/Value pairs in the MultiMap
+func (m *multiMap[Key, Value]) Count() int {
+	return m.count
+}
+
+func (m *multiMap[Key, Value]) CountKeys() int {
+	return m.trie.Count()
+}
+
+func (m *multiMap[Key, Value]) CountValues(k Key) int {
+	if s, ok := m.trie.Get(k); ok {
+		return s.Count()
+	}
+	return 0
+}
+
+func (m *multiMap[Key, Value]) IsEmpty() bool {
+	return m.trie.IsEmpty()
+}
+
+func (m *multiMap[Key, Value]) Add(k Key, v Value) MultiMap[Key, Value] {
+	s, ok := m.trie.Get(k)
+	if !ok {
+		s = NewSet[string]()
+	}
+	e := m.codec.Encode(v)
+	if s.Contains(e) {
+		return m
+	}
+	return &multiMap[Key, Value]{
+		trie:  m.trie.Put(k, s.Add(e)),
+		codec: m.codec,
+		count: m.count + 1,
+	}
+}
+
+// RemoveValue removes a Value from the set held by a Key. If the set is
+// left empty, the Key is removed as well
+func (m *multiMap[Key, Value]) RemoveValue(
+	k Key, v Value,
+) MultiMap[Key, Value] {
+	e := m.codec.Encode(v)
+	s, ok := m.trie.Get(k)
+	if !ok || !s.Contains(e) {
+		return m
+	}
+	res := &multiMap[Key, Value]{codec: m.codec, count: m.count - 1}
+	if s = s.Remove(e); s.IsEmpty() {
+		_, res.trie, _ = m.trie.Remove(k)
+		return res
+	}
+	res.trie = m.trie.Put(k, s)
+	return res
+}
+
+// RemoveKey removes a Key along with all of the Values it holds
+func (m *multiMap[Key, Value]) RemoveKey(k Key) MultiMap[Key, Value] {
+	if s, res, ok := m.trie.Remove(k); ok {
+		return &multiMap[Key, Value]{
+			trie:  res,
+			codec: m.codec,
+			count: m.count - s.Count(),
+		}
+	}
+	return m
+}
+
+func (m *multiMap[Key, Value]) Keys() iter.Seq[Key] {
+	return func(yield func(Key) bool) {
+		q := m.trie.Select().All()
+		for p, rest, ok := q.Next(); ok; p, rest, ok = rest.Next() {
+			if !yield(p.Key()) {
+				return
+			}
+		}
+	}
+}
+
+// All iterates over the Key/Value pairs of the MultiMap, ordered by Key
+// and then by the encoding of each Value
+func (m *multiMap[Key, Value]) All() iter.Seq2[Key, Value] {
+	return m.pairs(m.trie.Select().All(), Set[string].All)
+}
+
+// Descending iterates over the Key/Value pairs of the MultiMap in the
+// reverse order of All
+func (m *multiMap[Key, Value]) Descending() iter.Seq2[Key, Value] {
+	return m.pairs(m.trie.Select().Descending().All(), Set[string].Descending)
+}
+
+func (m *multiMap[Key, Value]) pairs(
+	q Query[Key, Set[string]], values func(Set[string]) iter.Seq[string],
+) iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		for p, rest, ok := q.Next(); ok; p, rest, ok = rest.Next() {
+			for v := range values(p.Value()) {
+				if !yield(p.Key(), m.codec.Decode(v)) {
+					return
+				}
+			}
+		}
+	}
+}
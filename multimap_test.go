@@ -0,0 +1,171 @@
+package trie_test
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/stretchr/testify/assert"
+)
+
+type tagged struct {
+	tag string
+	id  string
+}
+
+func collectPairs(seq func(func(string, string) bool)) []tagged {
+	res := []tagged{}
+	for k, v := range seq {
+		res = append(res, tagged{k, v})
+	}
+	return res
+}
+
+func TestMultiMap(t *testing.T) {
+	as := assert.New(t)
+
+	e := trie.NewMultiMap[string, string]()
+	as.True(e.IsEmpty())
+	as.Equal(0, e.Count())
+	as.Empty(slices.Collect(e.GetAll("red")))
+	as.Equal(0, e.CountValues("red"))
+
+	m := e.Add("red", "b").Add("red", "a").Add("blue", "c").Add("red", "a")
+	as.Equal(3, m.Count())
+	as.Equal(2, m.CountKeys())
+	as.Equal(2, m.CountValues("red"))
+	as.True(m.Contains("red", "a"))
+	as.False(m.Contains("red", "c"))
+	as.False(m.Contains("green", "a"))
+	as.Same(m, m.Add("blue", "c"))
+	as.True(e.IsEmpty())
+
+	as.Equal([]string{"a", "b"}, slices.Collect(m.GetAll("red")))
+	as.Equal([]string{"blue", "red"}, slices.Collect(m.Keys()))
+	as.Equal([]tagged{{"blue", "c"}, {"red", "a"}, {"red", "b"}},
+		collectPairs(m.All()))
+	as.Equal([]tagged{{"red", "b"}, {"red", "a"}, {"blue", "c"}},
+		collectPairs(m.Descending()))
+
+	r := m.RemoveValue("red", "a")
+	as.Equal(2, r.Count())
+	as.Equal([]string{"b"}, slices.Collect(r.GetAll("red")))
+	as.Same(r, r.RemoveValue("red", "a"))
+	as.Same(r, r.RemoveValue("green", "a"))
+	as.Equal(2, m.CountValues("red"))
+
+	r = r.RemoveValue("red", "b")
+	as.Equal(1, r.CountKeys())
+	as.Equal([]string{"blue"}, slices.Collect(r.Keys()))
+
+	r = m.RemoveKey("red")
+	as.Equal(1, r.Count())
+	as.Same(r, r.RemoveKey("red"))
+	as.Equal(3, m.Count())
+
+	for range m.All() {
+		break
+	}
+}
+
+func TestMultiMapEncodedValues(t *testing.T) {
+	as := assert.New(t)
+
+	id := func(i uint32) []byte {
+		return binary.BigEndian.AppendUint32(nil, i)
+	}
+	m := trie.NewMultiMap[string, []byte]()
+	for _, i := range []uint32{300, 2, 70000, 1} {
+		m = m.Add("user", id(i))
+	}
+	var ids []uint32
+	for v := range m.GetAll("user") {
+		ids = append(ids, binary.BigEndian.Uint32(v))
+	}
+	as.Equal([]uint32{1, 2, 300, 70000}, ids)
+	as.True(m.Contains("user", id(300)))
+}
+
+func TestMultiMapIntegerValues(t *testing.T) {
+	as := assert.New(t)
+
+	m := trie.NewMultiMapWith[string](trie.IntegerCodec[int]())
+	for _, id := range []int{300, -2, 70000, 1, math.MinInt, 0, math.MaxInt, 1} {
+		m = m.Add("user", id)
+	}
+	as.Equal(7, m.Count())
+	as.Equal([]int{math.MinInt, -2, 0, 1, 300, 70000, math.MaxInt},
+		slices.Collect(m.GetAll("user")))
+	as.True(m.Contains("user", -2))
+	as.False(m.Contains("user", 2))
+
+	m = m.RemoveValue("user", -2).RemoveValue("user", math.MinInt).
+		RemoveValue("user", math.MaxInt).Add("group", 7)
+	var all []string
+	for k, v := range m.All() {
+		all = append(all, fmt.Sprintf("%s=%d", k, v))
+	}
+	as.Equal([]string{
+		"group=7", "user=0", "user=1", "user=300", "user=70000",
+	}, all)
+
+	u := trie.NewMultiMapWith[string](trie.IntegerCodec[uint8]())
+	u = u.Add("a", 255).Add("a", 0).Add("a", 128)
+	as.Equal([]uint8{0, 128, 255}, slices.Collect(u.GetAll("a")))
+	i := trie.NewMultiMapWith[string](trie.IntegerCodec[int16]())
+	i = i.Add("a", 255).Add("a", -32768).Add("a", -1)
+	as.Equal([]int16{-32768, -1, 255}, slices.Collect(i.GetAll("a")))
+}
+
+func TestMultiMapRandom(t *testing.T) {
+	as := assert.New(t)
+	rng := rand.New(rand.NewSource(46))
+
+	m := trie.NewMultiMap[string, string](trie.WithPathCompression())
+	model := map[string]map[string]bool{}
+	count := 0
+	for i := 0; i < 2000; i++ {
+		k := string(rune('a' + rng.Intn(8)))
+		v := string(rune('a' + rng.Intn(8)))
+		if rng.Intn(3) == 0 {
+			m = m.RemoveValue(k, v)
+			if model[k][v] {
+				delete(model[k], v)
+				count--
+			}
+			if len(model[k]) == 0 {
+				delete(model, k)
+			}
+			continue
+		}
+		m = m.Add(k, v)
+		if model[k] == nil {
+			model[k] = map[string]bool{}
+		}
+		if !model[k][v] {
+			model[k][v] = true
+			count++
+		}
+	}
+
+	expected := []tagged{}
+	for k, vs := range model {
+		for v := range vs {
+			expected = append(expected, tagged{k, v})
+		}
+	}
+	sort.Slice(expected, func(i, j int) bool {
+		if expected[i].tag != expected[j].tag {
+			return expected[i].tag < expected[j].tag
+		}
+		return expected[i].id < expected[j].id
+	})
+	as.Equal(count, m.Count())
+	as.Equal(len(model), m.CountKeys())
+	as.Equal(expected, collectPairs(m.All()))
+}
@@ -3,7 +3,8 @@ package nibble
 import "github.com/caravan/go-immutable-trie/key"
 
 type (
-	// Nibbles is an interface used to consume 4-bit values from a Key
+	// Nibbles is an interface used to consume a Key one unit at a time.
+	// The width of each unit is determined by the Strategy that made them
 	Nibbles[Key key.Keyable] interface {
 		Consume() (uint8, Nibbles[Key], bool)
 		ByteOffset() int
@@ -44,7 +45,10 @@ func (n *nibbles[Key]) ByteOffset() int {
 
 func (n *nibbles[Key]) Branch(k Key) Nibbles[Key] {
 	b := makeNibbles[Key](k, n.off)
-	return &highNibbles[Key]{b}
+	if b.off < len(k) {
+		return &highNibbles[Key]{b}
+	}
+	return &emptyNibbles[Key]{b}
 }
 
 func (n *highNibbles[Key]) Consume() (uint8, Nibbles[Key], bool) {
@@ -63,7 +67,10 @@ func (n *lowNibbles[Key]) Consume() (uint8, Nibbles[Key], bool) {
 
 func (n *lowNibbles[Key]) Branch(k Key) Nibbles[Key] {
 	b := makeNibbles[Key](k, n.off)
-	return &lowNibbles[Key]{b}
+	if b.off < len(k) {
+		return &lowNibbles[Key]{b}
+	}
+	return &emptyNibbles[Key]{b}
 }
 
 func (n *emptyNibbles[Key]) Consume() (uint8, Nibbles[Key], bool) {
@@ -66,3 +66,22 @@ func TestEmptyNibbles(t *testing.T) {
 	as.False(ok)
 	as.Equal(0, r.ByteOffset())
 }
+
+func TestBranchPastEnd(t *testing.T) {
+	as := assert.New(t)
+
+	_, n, _ := nibble.Make([]byte{0x12}).Consume()
+	_, ok := consumeBranch(n, []byte{0x12})
+	as.True(ok)
+	_, ok = consumeBranch(n, []byte{})
+	as.False(ok)
+
+	_, n, _ = n.Consume()
+	_, ok = consumeBranch(n, []byte{0x12})
+	as.False(ok)
+}
+
+func consumeBranch(n nibble.Nibbles[[]byte], k []byte) (uint8, bool) {
+	res, _, ok := n.Branch(k).Consume()
+	return res, ok
+}
@@ -0,0 +1,93 @@
+package nibble
+
+import "github.com/caravan/go-immutable-trie/key"
+
+type (
+	// Strategy determines how many bits of a Key are consumed at each
+	// level of a Trie, and therefore how many buckets each level has
+	Strategy uint8
+
+	bitNibbles[Key key.Keyable] struct {
+		data  Key
+		bit   int
+		width Strategy
+	}
+)
+
+// Strategies for consuming Keys. The zero value consumes 4-bit nibbles
+const (
+	Bits4 Strategy = iota
+	Bits1
+	Bits2
+	Bits8
+)
+
+// Default is the Strategy used by a Trie unless otherwise configured
+const Default = Bits4
+
+// MaxSize is the largest number of buckets required by any Strategy
+const MaxSize = 256
+
+// MakeWith constructs a new set of Nibbles from the provided Key, consuming
+// it according to the provided Strategy
+func MakeWith[Key key.Keyable](s Strategy, k Key) Nibbles[Key] {
+	if s == Bits4 {
+		return Make(k)
+	}
+	return &bitNibbles[Key]{
+		data:  k,
+		width: s,
+	}
+}
+
+// IsValid returns whether the Strategy is one of those defined by this
+// package
+func (s Strategy) IsValid() bool {
+	return s <= Bits8
+}
+
+// Bits returns the number of bits consumed by each step of the Strategy
+func (s Strategy) Bits() int {
+	switch s {
+	case Bits1:
+		return 1
+	case Bits2:
+		return 2
+	case Bits8:
+		return 8
+	default:
+		return 4
+	}
+}
+
+// Size returns the number of distinct values produced by each step of the
+// Strategy, which is the number of buckets needed at each level of a Trie
+func (s Strategy) Size() int {
+	return 1 << s.Bits()
+}
+
+func (n *bitNibbles[Key]) Consume() (uint8, Nibbles[Key], bool) {
+	if n.bit >= len(n.data)*8 {
+		return 0, n, false
+	}
+	bits := n.width.Bits()
+	shift := 8 - bits - n.bit%8
+	res := n.data[n.bit/8] >> shift & uint8(n.width.Size()-1)
+	return res, &bitNibbles[Key]{
+		data:  n.data,
+		bit:   n.bit + bits,
+		width: n.width,
+	}, true
+}
+
+func (n *bitNibbles[_]) ByteOffset() int {
+	return n.bit / 8
+}
+
+func (n *bitNibbles[Key]) Branch(k Key) Nibbles[Key] {
+	return &bitNibbles[Key]{
+		data:  k,
+		bit:   n.bit,
+		width: n.width,
+	}
+}
@@ -0,0 +1,71 @@
+package nibble_test
+
+import (
+	"testing"
+
+	"github.com/caravan/go-immutable-trie/nibble"
+	"github.com/stretchr/testify/assert"
+)
+
+func consumeAll(n nibble.Nibbles[string]) []uint8 {
+	var res []uint8
+	for f, r, ok := n.Consume(); ok; f, r, ok = r.Consume() {
+		res = append(res, f)
+	}
+	return res
+}
+
+func TestStrategySizes(t *testing.T) {
+	as := assert.New(t)
+	as.Equal(nibble.Bits4, nibble.Default)
+	as.Equal(nibble.Size, nibble.Default.Size())
+	as.Equal(2, nibble.Bits1.Size())
+	as.Equal(4, nibble.Bits2.Size())
+	as.Equal(nibble.MaxSize, nibble.Bits8.Size())
+
+	for _, s := range []nibble.Strategy{
+		nibble.Bits1, nibble.Bits2, nibble.Bits4, nibble.Bits8,
+	} {
+		as.True(s.IsValid())
+	}
+	as.False(nibble.Strategy(8).IsValid())
+}
+
+func TestStrategyConsume(t *testing.T) {
+	as := assert.New(t)
+
+	as.Equal([]uint8{0x6, 0x8, 0x6, 0x9},
+		consumeAll(nibble.MakeWith(nibble.Bits4, "hi")))
+	as.Equal([]uint8{0x68, 0x69},
+		consumeAll(nibble.MakeWith(nibble.Bits8, "hi")))
+	as.Equal([]uint8{1, 2, 2, 0, 1, 2, 2, 1},
+		consumeAll(nibble.MakeWith(nibble.Bits2, "hi")))
+	as.Equal([]uint8{0, 1, 1, 0, 1, 0, 0, 0},
+		consumeAll(nibble.MakeWith(nibble.Bits1, "h")))
+	as.Nil(consumeAll(nibble.MakeWith(nibble.Bits1, "")))
+}
+
+func TestStrategyOffsets(t *testing.T) {
+	as := assert.New(t)
+
+	n := nibble.MakeWith(nibble.Bits2, "hi")
+	as.Equal(0, n.ByteOffset())
+	for i := 0; i < 4; i++ {
+		_, n, _ = n.Consume()
+	}
+	as.Equal(1, n.ByteOffset())
+
+	b := n.Branch("ho")
+	f, _, ok := b.Consume()
+	as.True(ok)
+	as.Equal(uint8(1), f)
+	as.Equal(1, b.ByteOffset())
+
+	_, r, ok := nibble.MakeWith(nibble.Bits8, "h").Consume()
+	as.True(ok)
+	f, r2, ok := r.Consume()
+	as.False(ok)
+	as.Equal(uint8(0), f)
+	as.Equal(r, r2)
+	as.Equal(1, r2.ByteOffset())
+}
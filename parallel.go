@@ -0,0 +1,249 @@
+package trie
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/caravan/go-immutable-trie/key"
+)
+
+type (
+	// Combiner merges two partial Results of a parallel reduction
+	Combiner[Result any] func(Result, Result) Result
+
+	// task is a unit of parallel work. It covers either a node's entire
+	// subtree, or only the node's own Pair once its buckets have been
+	// split off into separate tasks
+	task[Key key.Keyable, Value any] struct {
+		*trie[Key, Value]
+		single bool
+	}
+
+	// partition is a group of Pairs whose Keys share their first depth
+	// units. It's either built into a subtree by a single task, or divided
+	// by the next unit of its Keys into partitions of its own
+	partition[Key key.Keyable, Value any] struct {
+		pairs []pair[Key, Value]
+		depth int
+		built *trie[Key, Value]
+
+		// parts holds the partitions of a divided partition, indexed by
+		// unit, and least holds the Pair whose Key has no more units
+		parts []*partition[Key, Value]
+		least *pair[Key, Value]
+	}
+)
+
+// tasksPerWorker is the number of tasks that a parallel traversal attempts
+// to create for each worker, so that uneven subtrees balance out
+const tasksPerWorker = 4
+
+// ParallelForEach calls the provided function for every Pair in the Trie,
+// fanning out across the Trie's bucket subtrees using the requested number
+// of worker goroutines. If workers is less than one, GOMAXPROCS is used.
+// The function must be safe for concurrent use, and Pairs are not visited
+// in any particular order
+func ParallelForEach[Key key.Keyable, Value any](
+	t Trie[Key, Value], workers int, fn ForEach[Key, Value],
+) {
+	root, ok := t.(*trie[Key, Value])
+	if !ok {
+		return
+	}
+	tasks := splitTasks(root, workerCount(workers))
+	runTasks(len(tasks), workers, func(i int) {
+		tasks[i].forEach(fn)
+	})
+}
+
+// ParallelReduce maps every Pair in the Trie to a Result and combines
+// those Results into one, fanning out across the Trie's bucket subtrees
+// using the requested number of worker goroutines. If workers is less than
+// one, GOMAXPROCS is used. The Combiner must be associative, but needn't be
+// commutative, as partial Results are combined in Key order. An empty Trie
+// reduces to the zero Result
+func ParallelReduce[Key key.Keyable, Value any, Result any](
+	t Trie[Key, Value], workers int,
+	mapFn Mapper[Key, Value, Result], combine Combiner[Result],
+) Result {
+	var res Result
+	root, ok := t.(*trie[Key, Value])
+	if !ok {
+		return res
+	}
+	tasks := splitTasks(root, workerCount(workers))
+	partials := make([]Result, len(tasks))
+	runTasks(len(tasks), workers, func(i int) {
+		first := true
+		tasks[i].forEach(func(k Key, v Value) {
+			m := mapFn(k, v)
+			if first {
+				partials[i] = m
+				first = false
+				return
+			}
+			partials[i] = combine(partials[i], m)
+		})
+	})
+	res = partials[0]
+	for _, p := range partials[1:] {
+		res = combine(res, p)
+	}
+	return res
+}
+
+func workerCount(workers int) int {
+	if workers < 1 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return workers
+}
+
+// splitTasks splits the subtrees of a Trie into their buckets, level by
+// level, until there are enough tasks to keep the workers busy. The
+// resulting tasks remain in Key order
+func splitTasks[Key key.Keyable, Value any](
+	root *trie[Key, Value], workers int,
+) []task[Key, Value] {
+	res := []task[Key, Value]{{trie: root}}
+	target := workers * tasksPerWorker
+	for len(res) < target {
+		next := make([]task[Key, Value], 0, len(res)*root.width())
+		split := false
+		for _, t := range res {
+			if t.single || t.buckets == nil {
+				next = append(next, t)
+				continue
+			}
+			next = append(next, task[Key, Value]{trie: t.trie, single: true})
+			for _, bucket := range t.buckets.nodes() {
+				next = append(next, task[Key, Value]{trie: bucket})
+			}
+			split = true
+		}
+		if !split {
+			break
+		}
+		res = next
+	}
+	return res
+}
+
+// split divides partitions, level by level, until there are enough of
+// them to keep the workers busy, returning those left to be built. A
+// partition of fewer than two Pairs isn't worth dividing
+func (p *partition[Key, Value]) split(
+	root *trie[Key, Value], target int,
+) []*partition[Key, Value] {
+	res := []*partition[Key, Value]{p}
+	for len(res) < target {
+		next := make([]*partition[Key, Value], 0, len(res)*root.width())
+		split := false
+		for _, part := range res {
+			if len(part.pairs) < 2 {
+				next = append(next, part)
+				continue
+			}
+			for _, sub := range part.divide(root) {
+				if sub != nil {
+					next = append(next, sub)
+				}
+			}
+			split = true
+		}
+		if !split {
+			break
+		}
+		res = next
+	}
+	return res
+}
+
+func (p *partition[Key, Value]) divide(
+	root *trie[Key, Value],
+) []*partition[Key, Value] {
+	p.parts = make([]*partition[Key, Value], root.width())
+	for i := range p.pairs {
+		pr := &p.pairs[i]
+		idx, _, ok := skipUnits(root.nibbles(pr.key), p.depth).Consume()
+		if !ok {
+			least := *pr
+			p.least = &least
+			continue
+		}
+		if p.parts[idx] == nil {
+			p.parts[idx] = &partition[Key, Value]{depth: p.depth + 1}
+		}
+		p.parts[idx].pairs = append(p.parts[idx].pairs, *pr)
+	}
+	p.pairs = nil
+	return p.parts
+}
+
+// assemble joins the subtrees built for a partition's descendants into the
+// subtree for the partition itself
+func (p *partition[Key, Value]) assemble(
+	root *trie[Key, Value],
+) *trie[Key, Value] {
+	if p.parts == nil {
+		return p.built
+	}
+	dense := make([]*trie[Key, Value], len(p.parts))
+	for idx, sub := range p.parts {
+		if sub != nil {
+			dense[idx] = sub.assemble(root)
+		}
+	}
+	res := &trie[Key, Value]{
+		buckets: makeBuckets(dense, 0),
+		cfg:     root.cfg,
+	}
+	if p.least != nil {
+		res.pair = *p.least
+		return res
+	}
+	bucket, idx := res.leastBucket()
+	res.pair = bucket.pair
+	res.buckets = res.buckets.with(idx, bucket.promote())
+	return res
+}
+
+func runTasks(count int, workers int, run func(int)) {
+	indexes := make(chan int, count)
+	for i := 0; i < count; i++ {
+		indexes <- i
+	}
+	close(indexes)
+
+	w := workerCount(workers)
+	if w > count {
+		w = count
+	}
+
+	var wg sync.WaitGroup
+	for ; w > 0; w-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				run(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (t task[Key, Value]) forEach(fn ForEach[Key, Value]) {
+	if t.single {
+		fn(t.key, t.value)
+		return
+	}
+	t.trie.forEach(fn)
+}
+
+func (t *trie[Key, Value]) forEach(fn ForEach[Key, Value]) {
+	fn(t.key, t.value)
+	for _, bucket := range t.buckets.nodes() {
+		bucket.forEach(fn)
+	}
+}
@@ -0,0 +1,63 @@
+package trie_test
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallelForEach(t *testing.T) {
+	as := assert.New(t)
+
+	tr := makeLargeTrie(50000)
+	var cnt, sum int64
+	seen := sync.Map{}
+	trie.ParallelForEach(tr, 8, func(k string, v int) {
+		atomic.AddInt64(&cnt, 1)
+		atomic.AddInt64(&sum, int64(v))
+		_, dup := seen.LoadOrStore(k, v)
+		as.False(dup)
+	})
+	as.Equal(int64(50000), cnt)
+	as.Equal(int64(49999*50000/2), sum)
+
+	cnt = 0
+	trie.ParallelForEach(makeTestTrie(), 0, func(string, int) {
+		atomic.AddInt64(&cnt, 1)
+	})
+	as.Equal(int64(len(testMap)), cnt)
+
+	trie.ParallelForEach(trie.New[string, int](), 4, func(string, int) {
+		as.Fail("empty trie visited")
+	})
+}
+
+func TestParallelReduce(t *testing.T) {
+	as := assert.New(t)
+
+	tr := makeLargeTrie(50000)
+	sum := trie.ParallelReduce(tr, 8,
+		func(_ string, v int) int { return v },
+		func(l, r int) int { return l + r },
+	)
+	as.Equal(49999*50000/2, sum)
+
+	tr = makeTestTrie()
+	for _, workers := range []int{0, 1, 3, 16} {
+		keys := trie.ParallelReduce(tr, workers,
+			func(k string, _ int) string { return k },
+			func(l, r string) string { return l + "," + r },
+		)
+		as.Equal(strings.Join(tr.Select().All().Keys(), ","), keys)
+	}
+
+	empty := trie.ParallelReduce(trie.New[string, int](), 4,
+		func(_ string, v int) int { return v },
+		func(l, r int) int { return l + r },
+	)
+	as.Equal(0, empty)
+}
@@ -0,0 +1,139 @@
+package trie_test
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/stretchr/testify/assert"
+)
+
+type version struct {
+	trie.Trie[string, int]
+	pairs []testEntry
+}
+
+func makeVersion(t trie.Trie[string, int]) version {
+	var pairs []testEntry
+	t.Select().All().ForEach(func(k string, v int) {
+		pairs = append(pairs, testEntry{k, v})
+	})
+	return version{t, pairs}
+}
+
+func (v version) check(as *assert.Assertions, msg string) bool {
+	var pairs []testEntry
+	v.Select().All().ForEach(func(k string, v int) {
+		pairs = append(pairs, testEntry{k, v})
+	})
+	ok := as.Equal(v.pairs, pairs, msg)
+	ok = as.Equal(len(v.pairs), v.Count(), msg) && ok
+	for _, p := range v.pairs {
+		res, found := v.Get(p.key)
+		ok = as.True(found, msg) && as.Equal(p.value, res, msg) && ok
+	}
+	return as.Nil(v.Validate(), msg) && ok
+}
+
+// pickVersion mostly chooses among the most recent versions, so that the
+// Tries being operated on grow, while still revisiting older ones
+func pickVersion(rng *rand.Rand, versions []version) version {
+	if rng.Intn(4) == 0 {
+		return versions[rng.Intn(len(versions))]
+	}
+	return versions[len(versions)-1-rng.Intn(min(len(versions), 8))]
+}
+
+func TestPersistence(t *testing.T) {
+	for name, opts := range layouts {
+		t.Run(name, func(t *testing.T) {
+			as := assert.New(t)
+			rng := rand.New(rand.NewSource(42))
+
+			versions := []version{makeVersion(trie.New[string, int](opts...))}
+			for i := 0; i < 1500; i++ {
+				base := pickVersion(rng, versions)
+				k := randomKey(rng)
+				if len(base.pairs) > 0 && rng.Intn(2) == 0 {
+					// favor Keys that are present, and their prefixes
+					k = base.pairs[rng.Intn(len(base.pairs))].key
+					k = k[:len(k)-rng.Intn(len(k)/4+1)]
+				}
+				var next []trie.Trie[string, int]
+				switch rng.Intn(9) {
+				case 0, 1, 2:
+					next = append(next, base.Put(k, i))
+				case 3:
+					_, r, _ := base.Remove(k)
+					next = append(next, r)
+				case 4:
+					r, _ := base.RemovePrefix(k)
+					next = append(next, r)
+				case 5:
+					next = append(next, base.Rest())
+				case 6:
+					lo, hi := base.SplitAt(k)
+					next = append(next, lo, hi)
+				case 7:
+					other := pickVersion(rng, versions)
+					next = append(next, trie.Join(base, other))
+				default:
+					next = append(next, trie.FilterTrie(base,
+						func(k string, _ int) bool {
+							return !strings.HasSuffix(k, "a")
+						},
+					))
+				}
+				for _, n := range next {
+					versions = append(versions, makeVersion(n))
+				}
+
+				if i%100 == 0 {
+					for j, v := range versions {
+						if !v.check(as, fmt.Sprintf("step %d, version %d", i, j)) {
+							return
+						}
+					}
+				}
+			}
+
+			for j, v := range versions {
+				if !v.check(as, fmt.Sprintf("version %d", j)) {
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestQueryPersistence(t *testing.T) {
+	as := assert.New(t)
+
+	tr := makeLargeTrie(500)
+	q := tr.Select().From("250")
+	keys := q.Keys()
+
+	p, rest, _ := q.Next()
+	as.Equal(keys[0], p.Key())
+	rest.Reverse().Take(10).Keys()
+	rest.Where(func(k string, _ int) bool {
+		return k[0] == '3'
+	}).Keys()
+	q.Reverse().Keys()
+	_, r, _ := q.Skip(10).Next()
+	r.Reverse().Keys()
+
+	as.Equal(keys, q.Keys())
+	as.Equal(keys[1:], rest.Keys())
+
+	d := tr.Select().Descending().From("250")
+	desc := d.Keys()
+	d.Reverse().Keys()
+	tr.Put("2500", 0).Select().From("250").Keys()
+	_, t2, _ := tr.Remove("249")
+	t2.Select().Descending().From("250").Keys()
+	as.Equal(desc, d.Keys())
+	as.Equal(500, tr.Count())
+}
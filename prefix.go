@@ -0,0 +1,55 @@
+package trie
+
+import (
+	"iter"
+
+	"github.com/caravan/go-immutable-trie/key"
+)
+
+func (t *trie[Key, Value]) LongestPrefix(k Key) (Pair[Key, Value], bool) {
+	var res *trie[Key, Value]
+	t.walkPrefixes(k, func(node *trie[Key, Value]) bool {
+		res = node
+		return true
+	})
+	if res != nil {
+		p := res.pair
+		return &p, true
+	}
+	return nil, false
+}
+
+func (t *trie[Key, Value]) AllPrefixesOf(k Key) iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		t.walkPrefixes(k, func(node *trie[Key, Value]) bool {
+			return yield(node.key, node.value)
+		})
+	}
+}
+
+// walkPrefixes follows the nibble path of a Key once, calling the provided
+// function for each node whose Key is a prefix of it, shortest first. The
+// walk stops early if the function returns false
+func (t *trie[Key, Value]) walkPrefixes(
+	k Key, fn func(*trie[Key, Value]) bool,
+) {
+	n := t.nibbles(k)
+	for node := t; node != nil && !key.GreaterThan[Key](node.key, k); {
+		if key.StartsWith(k, node.key) && !fn(node) {
+			return
+		}
+		idx, next, ok := node.skipped(n).Consume()
+		if !ok || node.buckets == nil {
+			return
+		}
+		node, n = node.buckets.get(idx), next
+	}
+}
+
+func (empty[Key, Value]) LongestPrefix(Key) (Pair[Key, Value], bool) {
+	return nil, false
+}
+
+func (empty[Key, Value]) AllPrefixesOf(Key) iter.Seq2[Key, Value] {
+	return func(func(Key, Value) bool) {}
+}
@@ -0,0 +1,92 @@
+package trie_test
+
+import (
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeRouteTrie() trie.Trie[string, int] {
+	return trie.From(map[string]int{
+		"/":            1,
+		"/api":         2,
+		"/api/v1":      3,
+		"/api/v1/user": 4,
+		"/apix":        5,
+		"/static":      6,
+	})
+}
+
+func TestLongestPrefix(t *testing.T) {
+	as := assert.New(t)
+
+	tr := makeRouteTrie()
+	for probe, expected := range map[string]string{
+		"/api/v1/users/12": "/api/v1/user",
+		"/api/v1/":         "/api/v1",
+		"/api/v2":          "/api",
+		"/apix/y":          "/apix",
+		"/api":             "/api",
+		"/favicon.ico":     "/",
+	} {
+		p, ok := tr.LongestPrefix(probe)
+		as.True(ok, probe)
+		as.Equal(expected, p.Key(), probe)
+	}
+
+	p, ok := tr.LongestPrefix("api")
+	as.False(ok)
+	as.Nil(p)
+
+	p, ok = tr.Put("", 0).LongestPrefix("api")
+	as.True(ok)
+	as.Equal("", p.Key())
+	as.Equal(0, p.Value())
+
+	p, ok = trie.New[string, int]().LongestPrefix("/api")
+	as.False(ok)
+	as.Nil(p)
+}
+
+func TestAllPrefixesOf(t *testing.T) {
+	as := assert.New(t)
+
+	tr := makeRouteTrie()
+	var keys []string
+	var values []int
+	for k, v := range tr.AllPrefixesOf("/api/v1/users") {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	as.Equal([]string{"/", "/api", "/api/v1", "/api/v1/user"}, keys)
+	as.Equal([]int{1, 2, 3, 4}, values)
+
+	keys = nil
+	for k := range tr.AllPrefixesOf("/api/v1/users") {
+		keys = append(keys, k)
+		if len(keys) == 2 {
+			break
+		}
+	}
+	as.Equal([]string{"/", "/api"}, keys)
+
+	for range trie.New[string, int]().AllPrefixesOf("/api") {
+		as.Fail("empty trie yielded a prefix")
+	}
+}
+
+func TestLongestPrefixLarge(t *testing.T) {
+	as := assert.New(t)
+
+	tr := makeLargeTrie(10000)
+	p, ok := tr.LongestPrefix("99999")
+	as.True(ok)
+	as.Equal("9999", p.Key())
+
+	var keys []string
+	for k := range tr.AllPrefixesOf("1234567") {
+		keys = append(keys, k)
+	}
+	as.Equal([]string{"1", "12", "123", "1234"}, keys)
+}
@@ -1,6 +1,8 @@
 package trie
 
 import (
+	"context"
+
 	"github.com/caravan/go-immutable-trie/key"
 	"github.com/caravan/go-immutable-trie/nibble"
 )
@@ -10,6 +12,7 @@ type (
 		Select[Key, Value]
 		Ascending() Select[Key, Value]
 		Descending() Select[Key, Value]
+		Resume(string) (Query[Key, Value], error)
 	}
 
 	Select[Key key.Keyable, Value any] interface {
@@ -24,13 +27,34 @@ type (
 	Query[Key key.Keyable, Value any] interface {
 		Iterator[Key, Value]
 		ForEach(ForEach[Key, Value])
+		ForEachCtx(context.Context, Visitor[Key, Value]) error
+		WithContext(context.Context) Query[Key, Value]
 		Where(Filter[Key, Value]) Query[Key, Value]
 		While(Filter[Key, Value]) Query[Key, Value]
+		Take(int) Query[Key, Value]
+		Skip(int) Query[Key, Value]
+		Count() int
+		First() Pair[Key, Value]
+		Last() Pair[Key, Value]
+		Any(Filter[Key, Value]) bool
+		Every(Filter[Key, Value]) bool
+		Keys() []Key
+		Collect() Trie[Key, Value]
+		ToMap() map[string]Value
+		Reverse() Query[Key, Value]
+		Page(int) ([]Pair[Key, Value], Cursor[Key])
 	}
 
 	ForEach[Key key.Keyable, Value any] func(Key, Value)
 	Filter[Key key.Keyable, Value any]  func(Key, Value) bool
 
+	// Visitor is called for each Pair of a Query. Returning an error stops
+	// the iteration
+	Visitor[Key key.Keyable, Value any] func(Key, Value) error
+
+	// Reducer folds a Key/Value Pair into an accumulated Result
+	Reducer[Key key.Keyable, Value any, Result any] func(Result, Key, Value) Result
+
 	iterator[Key key.Keyable, Value any] struct {
 		parent     *iterator[Key, Value]
 		descending bool
@@ -38,6 +62,41 @@ type (
 		idx int
 	}
 
+	reverser[Key key.Keyable, Value any] interface {
+		reverse() Query[Key, Value]
+	}
+
+	directed interface {
+		isDescending() bool
+	}
+
+	// configured is implemented by Queries that know the configuration of
+	// the Trie they read, so that the Trie they Collect shares it
+	configured interface {
+		config() *config
+	}
+
+	// cancellable is implemented by Queries that can rebuild themselves so
+	// that every Pair they read, and not only those they produce, is
+	// subject to a canceller
+	cancellable[Key key.Keyable, Value any] interface {
+		withCanceller(*canceller) Query[Key, Value]
+	}
+
+	// canceller checks a context on behalf of ForEachCtx, recording the
+	// context's error if it stopped a Query that had Pairs left to read
+	canceller struct {
+		ctx       context.Context
+		countdown int
+		err       error
+	}
+
+	// checked reads Pairs from a Query until its canceller stops it
+	checked[Key key.Keyable, Value any] struct {
+		Query[Key, Value]
+		*canceller
+	}
+
 	fetcher[Key key.Keyable, Value any] func() (
 		Pair[Key, Value], Iterator[Key, Value],
 	)
@@ -55,6 +114,27 @@ type (
 		Query[Key, Value]
 		Filter[Key, Value]
 	}
+
+	take[Key key.Keyable, Value any] struct {
+		Query[Key, Value]
+		count int
+	}
+
+	skip[Key key.Keyable, Value any] struct {
+		Query[Key, Value]
+		count int
+	}
+
+	withContext[Key key.Keyable, Value any] struct {
+		Query[Key, Value]
+		ctx       context.Context
+		countdown int
+	}
+
+	mapped[Key key.Keyable, Value any, Result any] struct {
+		Query[Key, Value]
+		Mapper[Key, Value, Result]
+	}
 )
 
 func makeQuery[Key key.Keyable, Value any](
@@ -68,9 +148,9 @@ func makeQuery[Key key.Keyable, Value any](
 func (i *iterator[Key, Value]) mutate(
 	mutate func(*iterator[Key, Value]),
 ) *iterator[Key, Value] {
-	res := &(*i)
-	mutate(res)
-	return res
+	res := *i
+	mutate(&res)
+	return &res
 }
 
 func (i *iterator[Key, Value]) Ascending() Select[Key, Value] {
@@ -91,41 +171,50 @@ func (i *iterator[Key, Value]) All() Query[Key, Value] {
 }
 
 func (i *iterator[Key, Value]) From(k Key) Query[Key, Value] {
-	n := nibble.Make(k)
-	pos, ok := i.seek(k, n)
-	if !ok && i.descending {
-		return decorate(pos.prevParent())
-	}
-	return pos.decorate()
+	n := i.nibbles(k)
+	return decorate(i.seek(k, n))
 }
 
 func (i *iterator[Key, Value]) last() *iterator[Key, Value] {
-	if i.buckets != nil {
-		for idx := len(i.buckets) - 1; idx >= 0; idx-- {
-			if bucket := i.buckets[idx]; bucket != nil {
-				return i.setIndex(idx).child(bucket).last()
-			}
-		}
+	if idx, bucket := i.buckets.prev(nibble.MaxSize); bucket != nil {
+		return i.setIndex(idx).child(bucket).last()
 	}
 	return i.setIndex(-1)
 }
 
 func (i *iterator[Key, Value]) seek(
 	k Key, n nibble.Nibbles[Key],
-) (*iterator[Key, Value], bool) {
-	t := i.trie
-	if key.EqualTo[Key](t.pair.key, k) {
-		return i, true
-	}
-	if idx, n, ok := n.Consume(); ok && t.buckets != nil {
-		bucket := t.buckets[idx]
-		if bucket != nil {
-			parent := i.setIndex(int(idx))
-			child := parent.child(bucket)
-			return child.seek(k, n)
+) Iterator[Key, Value] {
+	switch key.Compare[Key](i.pair.key, k) {
+	case key.Equal:
+		return i
+	case key.Greater:
+		if i.descending {
+			return i.prevParent()
+		}
+		return i
+	}
+	if i.diverges(n) {
+		// every Key in this subtree is less than the one being sought
+		if i.descending {
+			return i.last()
 		}
+		return i.nextSibling()
+	}
+	idx, n, ok := i.skipped(n).Consume()
+	if !ok {
+		panic("programmer error: sought past a non-consumable key")
+	}
+	if bucket := i.buckets.get(idx); bucket != nil {
+		return i.setIndex(int(idx)).child(bucket).seek(k, n)
+	}
+	if i.descending {
+		return i.setIndex(int(idx)).prevBucket()
+	}
+	if res, ok := i.setIndex(int(idx) + 1).nextBucket(); ok {
+		return res
 	}
-	return i, false
+	return empty[Key, Value]{cfg: i.cfg}
 }
 
 func (i *iterator[Key, Value]) Next() (
@@ -150,17 +239,12 @@ func (i *iterator[Key, Value]) fetchNext() (
 	if res, ok := i.nextBucket(); ok {
 		return &p, res
 	}
-	return &p, empty[Key, Value]{}
+	return &p, empty[Key, Value]{cfg: i.cfg}
 }
 
 func (i *iterator[Key, Value]) nextBucket() (*iterator[Key, Value], bool) {
-	if i.buckets != nil {
-		for idx := i.idx; idx < len(i.buckets); idx++ {
-			if bucket := i.buckets[idx]; bucket != nil {
-				parent := i.setIndex(idx)
-				return parent.child(bucket), true
-			}
-		}
+	if idx, bucket := i.buckets.next(i.idx); bucket != nil {
+		return i.setIndex(idx).child(bucket), true
 	}
 	if parent := i.parent; parent != nil {
 		return parent.advanceIndex().nextBucket()
@@ -168,6 +252,15 @@ func (i *iterator[Key, Value]) nextBucket() (*iterator[Key, Value], bool) {
 	return nil, false
 }
 
+func (i *iterator[Key, Value]) nextSibling() Iterator[Key, Value] {
+	if parent := i.parent; parent != nil {
+		if res, ok := parent.advanceIndex().nextBucket(); ok {
+			return res
+		}
+	}
+	return empty[Key, Value]{cfg: i.cfg}
+}
+
 func (i *iterator[Key, Value]) fetchPrev() (
 	Pair[Key, Value], Iterator[Key, Value],
 ) {
@@ -179,16 +272,12 @@ func (i *iterator[Key, Value]) prevParent() Iterator[Key, Value] {
 	if parent := i.parent; parent != nil {
 		return parent.prevBucket()
 	}
-	return empty[Key, Value]{}
+	return empty[Key, Value]{cfg: i.cfg}
 }
 
 func (i *iterator[Key, Value]) prevBucket() *iterator[Key, Value] {
-	if i.buckets != nil {
-		for idx := i.idx - 1; idx >= 0; idx-- {
-			if bucket := i.buckets[idx]; bucket != nil {
-				return i.setIndex(idx).child(bucket).last()
-			}
-		}
+	if idx, bucket := i.buckets.prev(i.idx); bucket != nil {
+		return i.setIndex(idx).child(bucket).last()
 	}
 	return i.setIndex(-1)
 }
@@ -217,6 +306,29 @@ func (i *iterator[Key, Value]) decorate() Query[Key, Value] {
 	return decorate[Key, Value](i)
 }
 
+func (i *iterator[Key, Value]) config() *config {
+	return i.cfg
+}
+
+func (i *iterator[Key, Value]) isDescending() bool {
+	return i.descending
+}
+
+func (i *iterator[Key, Value]) reverse() Query[Key, Value] {
+	res := i.flip()
+	res.idx = 0
+	return res.decorate()
+}
+
+func (i *iterator[Key, Value]) flip() *iterator[Key, Value] {
+	res := *i
+	res.descending = !i.descending
+	if i.parent != nil {
+		res.parent = i.parent.flip()
+	}
+	return &res
+}
+
 func (w *where[Key, Value]) Next() (
 	Pair[Key, Value], Query[Key, Value], bool,
 ) {
@@ -233,6 +345,18 @@ func (w *where[Key, Value]) decorate() Query[Key, Value] {
 	return decorate[Key, Value](w)
 }
 
+func (w *where[Key, Value]) config() *config {
+	return configOf(w.Query)
+}
+
+func (w *where[Key, Value]) isDescending() bool {
+	return isDescending(w.Query)
+}
+
+func (w *where[Key, Value]) reverse() Query[Key, Value] {
+	return (&where[Key, Value]{w.Query.Reverse(), w.Filter}).decorate()
+}
+
 func (w *while[Key, Value]) Next() (
 	Pair[Key, Value], Query[Key, Value], bool,
 ) {
@@ -247,6 +371,266 @@ func (w *while[Key, Value]) decorate() Query[Key, Value] {
 	return decorate[Key, Value](w)
 }
 
+func (w *while[Key, Value]) config() *config {
+	return configOf(w.Query)
+}
+
+func (w *while[Key, Value]) isDescending() bool {
+	return isDescending(w.Query)
+}
+
+// reverse walks back over the run of Pairs that satisfy the Filter. If the
+// Pair at this position doesn't, it's the one that ended the run, and the
+// walk starts just before it
+func (w *while[Key, Value]) reverse() Query[Key, Value] {
+	q := w.Query.Reverse()
+	if p, c, ok := q.Next(); ok && !w.Filter(p.Key(), p.Value()) {
+		q = c
+	}
+	return (&while[Key, Value]{q, w.Filter}).decorate()
+}
+
+func (t *take[Key, Value]) Next() (
+	Pair[Key, Value], Query[Key, Value], bool,
+) {
+	if t.count > 0 {
+		if p, c, ok := t.Query.Next(); ok {
+			q := (&take[Key, Value]{c, t.count - 1}).decorate()
+			return p, q, true
+		}
+	}
+	return nil, decoratedEmpty[Key, Value](), false
+}
+
+func (t *take[Key, Value]) decorate() Query[Key, Value] {
+	return decorate[Key, Value](t)
+}
+
+func (t *take[Key, Value]) config() *config {
+	return configOf(t.Query)
+}
+
+func (t *take[Key, Value]) isDescending() bool {
+	return isDescending(t.Query)
+}
+
+func (t *take[Key, Value]) reverse() Query[Key, Value] {
+	if t.count > 0 {
+		return (&take[Key, Value]{t.Query.Reverse(), t.count}).decorate()
+	}
+	return decoratedEmpty[Key, Value]()
+}
+
+func (s *skip[Key, Value]) Next() (
+	Pair[Key, Value], Query[Key, Value], bool,
+) {
+	q := s.Query
+	for i := 0; i < s.count; i++ {
+		_, c, ok := q.Next()
+		if !ok {
+			return nil, decoratedEmpty[Key, Value](), false
+		}
+		q = c
+	}
+	return q.Next()
+}
+
+func (s *skip[Key, Value]) decorate() Query[Key, Value] {
+	return decorate[Key, Value](s)
+}
+
+func (s *skip[Key, Value]) config() *config {
+	return configOf(s.Query)
+}
+
+func (s *skip[Key, Value]) isDescending() bool {
+	return isDescending(s.Query)
+}
+
+func (s *skip[Key, Value]) reverse() Query[Key, Value] {
+	q := s.Query
+	for i := 0; i < s.count; i++ {
+		_, c, ok := q.Next()
+		if !ok {
+			return decoratedEmpty[Key, Value]()
+		}
+		q = c
+	}
+	return q.Reverse()
+}
+
+// contextCheckInterval is the number of Pairs a context-aware Query will
+// produce between checks of its Context
+const contextCheckInterval = 64
+
+// cancelled reports whether the context has been cancelled, checking it
+// once every contextCheckInterval calls. It's only called when a Pair
+// remains to be read, so an error it records means the Query stopped early
+func (c *canceller) cancelled() bool {
+	if c.countdown--; c.countdown > 0 {
+		return false
+	}
+	c.countdown = contextCheckInterval
+	c.err = c.ctx.Err()
+	return c.err != nil
+}
+
+// withCanceller subjects every Pair that a Query reads to a canceller. A
+// Query that filters or skips Pairs is rebuilt around its source, so that
+// it can be stopped while it passes over Pairs it won't produce
+func withCanceller[Key key.Keyable, Value any](
+	q Query[Key, Value], c *canceller,
+) Query[Key, Value] {
+	if d, ok := q.(*decorated[Key, Value]); ok {
+		if i, ok := d.Iterator.(cancellable[Key, Value]); ok {
+			return i.withCanceller(c)
+		}
+	}
+	return (&checked[Key, Value]{q, c}).decorate()
+}
+
+func (c *checked[Key, Value]) Next() (
+	Pair[Key, Value], Query[Key, Value], bool,
+) {
+	if p, rest, ok := c.Query.Next(); ok && !c.cancelled() {
+		return p, (&checked[Key, Value]{rest, c.canceller}).decorate(), true
+	}
+	return nil, decoratedEmpty[Key, Value](), false
+}
+
+func (c *checked[Key, Value]) config() *config {
+	return configOf(c.Query)
+}
+
+func (c *checked[Key, Value]) decorate() Query[Key, Value] {
+	return decorate[Key, Value](c)
+}
+
+func (w *where[Key, Value]) withCanceller(c *canceller) Query[Key, Value] {
+	return (&where[Key, Value]{withCanceller(w.Query, c), w.Filter}).decorate()
+}
+
+func (w *while[Key, Value]) withCanceller(c *canceller) Query[Key, Value] {
+	return (&while[Key, Value]{withCanceller(w.Query, c), w.Filter}).decorate()
+}
+
+func (t *take[Key, Value]) withCanceller(c *canceller) Query[Key, Value] {
+	return (&take[Key, Value]{withCanceller(t.Query, c), t.count}).decorate()
+}
+
+func (s *skip[Key, Value]) withCanceller(c *canceller) Query[Key, Value] {
+	return (&skip[Key, Value]{withCanceller(s.Query, c), s.count}).decorate()
+}
+
+func (w *withContext[Key, Value]) withCanceller(
+	c *canceller,
+) Query[Key, Value] {
+	return (&withContext[Key, Value]{
+		withCanceller(w.Query, c), w.ctx, w.countdown,
+	}).decorate()
+}
+
+func (m *mapped[Key, Value, Result]) withCanceller(
+	c *canceller,
+) Query[Key, Result] {
+	return Map(withCanceller(m.Query, c), m.Mapper)
+}
+
+func (w *withContext[Key, Value]) Next() (
+	Pair[Key, Value], Query[Key, Value], bool,
+) {
+	countdown := w.countdown
+	if countdown <= 0 {
+		if w.ctx.Err() != nil {
+			return nil, decoratedEmpty[Key, Value](), false
+		}
+		countdown = contextCheckInterval
+	}
+	if p, c, ok := w.Query.Next(); ok {
+		q := (&withContext[Key, Value]{c, w.ctx, countdown - 1}).decorate()
+		return p, q, true
+	}
+	return nil, decoratedEmpty[Key, Value](), false
+}
+
+func (w *withContext[Key, Value]) decorate() Query[Key, Value] {
+	return decorate[Key, Value](w)
+}
+
+func (w *withContext[Key, Value]) config() *config {
+	return configOf(w.Query)
+}
+
+func (w *withContext[Key, Value]) isDescending() bool {
+	return isDescending(w.Query)
+}
+
+func (w *withContext[Key, Value]) reverse() Query[Key, Value] {
+	return (&withContext[Key, Value]{w.Query.Reverse(), w.ctx, 0}).decorate()
+}
+
+// Map returns a Query that lazily transforms the Values produced by the
+// provided Query using the Mapper
+func Map[Key key.Keyable, Value any, Result any](
+	q Query[Key, Value], fn Mapper[Key, Value, Result],
+) Query[Key, Result] {
+	return (&mapped[Key, Value, Result]{q, fn}).decorate()
+}
+
+func (m *mapped[Key, Value, Result]) Next() (
+	Pair[Key, Result], Query[Key, Result], bool,
+) {
+	if p, c, ok := m.Query.Next(); ok {
+		k := p.Key()
+		res := &pair[Key, Result]{k, m.Mapper(k, p.Value())}
+		q := (&mapped[Key, Value, Result]{c, m.Mapper}).decorate()
+		return res, q, true
+	}
+	return nil, decoratedEmpty[Key, Result](), false
+}
+
+func (m *mapped[Key, Value, Result]) decorate() Query[Key, Result] {
+	return decorate[Key, Result](m)
+}
+
+func (m *mapped[Key, Value, Result]) config() *config {
+	return configOf(m.Query)
+}
+
+func (m *mapped[Key, Value, Result]) isDescending() bool {
+	return isDescending(m.Query)
+}
+
+func (m *mapped[Key, Value, Result]) reverse() Query[Key, Result] {
+	return Map(m.Query.Reverse(), m.Mapper)
+}
+
+// Reduce folds every Pair produced by the provided Query into a Result,
+// starting with the initial value
+func Reduce[Key key.Keyable, Value any, Result any](
+	q Query[Key, Value], fn Reducer[Key, Value, Result], init Result,
+) Result {
+	res := init
+	q.ForEach(func(k Key, v Value) {
+		res = fn(res, k, v)
+	})
+	return res
+}
+
+func isDescending(i any) bool {
+	if d, ok := i.(directed); ok {
+		return d.isDescending()
+	}
+	return false
+}
+
+func configOf(i any) *config {
+	if c, ok := i.(configured); ok {
+		return c.config()
+	}
+	return nil
+}
+
 func decorate[Key key.Keyable, Value any](
 	i Iterator[Key, Value],
 ) Query[Key, Value] {
@@ -259,6 +643,30 @@ func (d *decorated[Key, Value]) ForEach(f ForEach[Key, Value]) {
 	}
 }
 
+// ForEachCtx visits each Pair of the Query. The context is checked as the
+// Trie is walked, including while a filter passes over Pairs it rejects,
+// rather than only between the Pairs that are visited. The context's error
+// is returned only if it stopped the iteration before every Pair was
+// visited
+func (d *decorated[Key, Value]) ForEachCtx(
+	ctx context.Context, v Visitor[Key, Value],
+) error {
+	c := &canceller{ctx: ctx}
+	q := withCanceller[Key, Value](d, c)
+	for p, rest, ok := q.Next(); ok; p, rest, ok = rest.Next() {
+		if err := v(p.Key(), p.Value()); err != nil {
+			return err
+		}
+	}
+	return c.err
+}
+
+func (d *decorated[Key, Value]) WithContext(
+	ctx context.Context,
+) Query[Key, Value] {
+	return (&withContext[Key, Value]{d, ctx, 0}).decorate()
+}
+
 func (d *decorated[Key, Value]) Where(f Filter[Key, Value]) Query[Key, Value] {
 	return (&where[Key, Value]{d, f}).decorate()
 }
@@ -266,3 +674,97 @@ func (d *decorated[Key, Value]) Where(f Filter[Key, Value]) Query[Key, Value] {
 func (d *decorated[Key, Value]) While(f Filter[Key, Value]) Query[Key, Value] {
 	return (&while[Key, Value]{d, f}).decorate()
 }
+
+func (d *decorated[Key, Value]) Take(count int) Query[Key, Value] {
+	return (&take[Key, Value]{d, count}).decorate()
+}
+
+func (d *decorated[Key, Value]) Skip(count int) Query[Key, Value] {
+	return (&skip[Key, Value]{d, count}).decorate()
+}
+
+func (d *decorated[Key, Value]) Count() int {
+	res := 0
+	for _, q, ok := d.Next(); ok; _, q, ok = q.Next() {
+		res++
+	}
+	return res
+}
+
+func (d *decorated[Key, Value]) First() Pair[Key, Value] {
+	if p, _, ok := d.Next(); ok {
+		return p
+	}
+	return nil
+}
+
+func (d *decorated[Key, Value]) Last() Pair[Key, Value] {
+	var res Pair[Key, Value]
+	for p, q, ok := d.Next(); ok; p, q, ok = q.Next() {
+		res = p
+	}
+	return res
+}
+
+func (d *decorated[Key, Value]) Any(f Filter[Key, Value]) bool {
+	for p, q, ok := d.Next(); ok; p, q, ok = q.Next() {
+		if f(p.Key(), p.Value()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *decorated[Key, Value]) Every(f Filter[Key, Value]) bool {
+	for p, q, ok := d.Next(); ok; p, q, ok = q.Next() {
+		if !f(p.Key(), p.Value()) {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *decorated[Key, Value]) Keys() []Key {
+	var res []Key
+	d.ForEach(func(k Key, _ Value) {
+		res = append(res, k)
+	})
+	return res
+}
+
+// Collect returns a Trie of the Query's Pairs, configured with the same
+// Options as the Trie that the Query reads
+func (d *decorated[Key, Value]) Collect() Trie[Key, Value] {
+	var res Trie[Key, Value] = empty[Key, Value]{cfg: configOf(d)}
+	d.ForEach(func(k Key, v Value) {
+		res = res.Put(k, v)
+	})
+	return res
+}
+
+func (d *decorated[Key, Value]) ToMap() map[string]Value {
+	res := map[string]Value{}
+	d.ForEach(func(k Key, v Value) {
+		res[string(k)] = v
+	})
+	return res
+}
+
+// Reverse returns a Query that walks in the opposite direction from this
+// Query's position, starting with the Pair found there. Any filters that
+// have been applied to this Query continue to apply, so a filtered Query
+// reverses through the matching Pairs that precede its position
+func (d *decorated[Key, Value]) Reverse() Query[Key, Value] {
+	if r, ok := d.Iterator.(reverser[Key, Value]); ok {
+		return r.reverse()
+	}
+	return decoratedEmpty[Key, Value]()
+}
+
+func (d *decorated[Key, Value]) config() *config {
+	return configOf(d.Iterator)
+}
+
+func (d *decorated[Key, Value]) isDescending() bool {
+	return isDescending(d.Iterator)
+}
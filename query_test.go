@@ -1,7 +1,10 @@
 package trie_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"testing"
 
@@ -26,6 +29,34 @@ func testResults(t *testing.T, q trie.Query[string, int], entries []testEntry) {
 	as.Equal(len(entries), i)
 }
 
+func TestQueryLeavesOriginal(t *testing.T) {
+	as := assert.New(t)
+
+	for _, sel := range []trie.Select[string, int]{
+		makeTestTrie().Select(),
+		makeTestTrie().Select().Descending(),
+	} {
+		var keys []string
+		var queries []trie.Query[string, int]
+		q := sel.All()
+		for p, r, ok := q.Next(); ok; p, r, ok = r.Next() {
+			keys = append(keys, p.Key())
+			queries = append(queries, q)
+			q = r
+		}
+		as.Len(keys, len(testMap))
+		for i, q := range queries {
+			p, _, ok := q.Next()
+			as.True(ok)
+			as.Equal(keys[i], p.Key())
+		}
+	}
+
+	sel := makeTestTrie().Select()
+	as.Equal("you", sel.Descending().All().First().Key())
+	as.Equal("a", sel.All().First().Key())
+}
+
 func TestWhereQuery(t *testing.T) {
 	q := makeTestTrie().Select().All().Where(func(k string, v int) bool {
 		return k[0] == 'h'
@@ -200,3 +231,455 @@ func TestDescendingFromBetween(t *testing.T) {
 		{"a", 16},
 	})
 }
+
+func TestTakeSkipQuery(t *testing.T) {
+	q := makeTestTrie().Select().All().Skip(2).Take(3)
+	testResults(t, q, []testEntry{
+		{"bit", 1024},
+		{"curious", 128},
+		{"hear", 32},
+	})
+
+	q = makeTestTrie().Select().Descending().All().Take(2)
+	testResults(t, q, []testEntry{
+		{"you", 37},
+		{"today", 4},
+	})
+
+	q = makeTestTrie().Select().All().Skip(20)
+	testResults(t, q, []testEntry{})
+
+	q = makeTestTrie().Select().All().Take(0)
+	testResults(t, q, []testEntry{})
+}
+
+func TestMapReduceQuery(t *testing.T) {
+	as := assert.New(t)
+
+	query := func() trie.Query[string, int] {
+		return makeTestTrie().Select().All().Where(func(k string, _ int) bool {
+			return k[0] == 't'
+		})
+	}
+	mapped := func() trie.Query[string, string] {
+		return trie.Map(query(), func(k string, v int) string {
+			return fmt.Sprintf("%s:%d", k, v)
+		})
+	}
+	as.Equal([]string{"there", "to", "today"}, mapped().Keys())
+	as.Equal("to:64", mapped().Skip(1).First().Value())
+
+	sum := func(acc int, _ string, v int) int {
+		return acc + v
+	}
+	as.Equal(2+64+4, trie.Reduce(query(), sum, 0))
+	as.Equal(-1, trie.Reduce(query().Take(0), sum, -1))
+}
+
+func TestTerminalQueries(t *testing.T) {
+	as := assert.New(t)
+
+	tr := makeTestTrie()
+	as.Equal(len(testMap), tr.Select().All().Count())
+	as.Equal("a", tr.Select().All().First().Key())
+	as.Equal("you", tr.Select().All().Last().Key())
+	as.Equal("a", tr.Select().Descending().All().Last().Key())
+	as.Nil(tr.Select().All().Skip(20).First())
+	as.Nil(tr.Select().All().Skip(20).Last())
+
+	as.True(tr.Select().All().Any(func(k string, _ int) bool {
+		return k == "hello"
+	}))
+	as.False(tr.Select().All().Any(func(_ string, v int) bool {
+		return v > 2048
+	}))
+	as.True(tr.Select().All().Every(func(_ string, v int) bool {
+		return v > 0
+	}))
+	as.False(tr.Select().All().Every(func(k string, _ int) bool {
+		return k != "to"
+	}))
+
+	as.Equal([]string{"to", "today", "you"}, tr.Select().From("to").Keys())
+	as.Equal(testMap, tr.Select().All().ToMap())
+
+	c := tr.Select().All().Where(func(k string, _ int) bool {
+		return k[0] == 'h'
+	}).Collect()
+	as.Equal(3, c.Count())
+	v, ok := c.Get("hello")
+	as.True(ok)
+	as.Equal(1, v)
+
+	e := trie.New[string, int]().Select().All()
+	as.Equal(0, e.Count())
+	as.Nil(e.First())
+	as.Nil(e.Keys())
+	as.True(e.Collect().IsEmpty())
+	as.Equal(map[string]int{}, e.ToMap())
+}
+
+func TestCollectKeepsOptions(t *testing.T) {
+	as := assert.New(t)
+
+	byValue := func(_ string, v int) float64 { return float64(v) }
+	for name, opts := range layouts {
+		opts := append(slices.Clone(opts), trie.WithScore(byValue))
+		tr := trie.New[string, int](opts...)
+		for k, v := range testMap {
+			tr = tr.Put(k, v)
+		}
+		as.Equal(tr.Stats(), tr.Select().All().Collect().Stats(), name)
+
+		h, err := tr.Match("h*")
+		as.Nil(err)
+		for _, q := range []trie.Query[string, int]{
+			tr.Select().From("how").Where(func(k string, _ int) bool {
+				return len(k) > 2
+			}).Take(3).Skip(1),
+			tr.Select().Descending().From("t").While(func(string, int) bool {
+				return true
+			}),
+			tr.Select().All().WithContext(context.Background()),
+			h,
+			tr.Select().From("zzz"),
+		} {
+			res := q.Collect()
+			as.Nil(res.Validate(), name)
+			as.ElementsMatch(q.Keys(), res.Select().All().Keys(), name)
+
+			// the Collected Trie is scored like the original
+			res = res.Put("x", 2000)
+			as.Len(res.Stats().Occupancy, len(tr.Stats().Occupancy), name)
+			as.Equal([]string{"x=2000"}, completed(res.Complete("", 1, nil)), name)
+		}
+	}
+}
+
+func TestReverseQuery(t *testing.T) {
+	q := makeTestTrie().Select().From("hear").Reverse()
+	testResults(t, q, []testEntry{
+		{"hear", 32},
+		{"curious", 128},
+		{"bit", 1024},
+		{"are", 5},
+		{"a", 16},
+	})
+
+	q = makeTestTrie().Select().Descending().From("how").Reverse()
+	testResults(t, q, []testEntry{
+		{"how", 9},
+		{"there", 2},
+		{"to", 64},
+		{"today", 4},
+		{"you", 37},
+	})
+
+	q = makeTestTrie().Select().Descending().All().Reverse()
+	testResults(t, q, []testEntry{
+		{"you", 37},
+	})
+
+	q = makeTestTrie().Select().All().Reverse().Reverse().Take(2)
+	testResults(t, q, []testEntry{
+		{"a", 16},
+		{"are", 5},
+	})
+}
+
+func TestReverseMidIteration(t *testing.T) {
+	as := assert.New(t)
+
+	q := makeTestTrie().Select().All()
+	for i := 0; i < 5; i++ {
+		_, q, _ = q.Next()
+	}
+	as.Equal("hello", q.First().Key())
+
+	testResults(t, q.Reverse(), []testEntry{
+		{"hello", 1},
+		{"hear", 32},
+		{"curious", 128},
+		{"bit", 1024},
+		{"are", 5},
+		{"a", 16},
+	})
+	testResults(t, q, []testEntry{
+		{"hello", 1},
+		{"how", 9},
+		{"there", 2},
+		{"to", 64},
+		{"today", 4},
+		{"you", 37},
+	})
+}
+
+func TestReverseFilteredQuery(t *testing.T) {
+	long := func(k string, _ int) bool {
+		return len(k) > 3
+	}
+
+	q := makeTestTrie().Select().From("to").Where(long).Reverse().Take(3)
+	testResults(t, q, []testEntry{
+		{"there", 2},
+		{"hello", 1},
+		{"hear", 32},
+	})
+
+	startsWithH := func(k string, _ int) bool {
+		return k[0] == 'h'
+	}
+	q = makeTestTrie().Select().From("m").Where(startsWithH).Reverse().Take(10)
+	testResults(t, q, []testEntry{
+		{"how", 9},
+		{"hello", 1},
+		{"hear", 32},
+	})
+
+	q = makeTestTrie().Select().From("b").Where(func(k string, _ int) bool {
+		return k[0] == 'a' || k[0] == 'y'
+	}).Reverse()
+	testResults(t, q, []testEntry{
+		{"are", 5},
+		{"a", 16},
+	})
+
+	q = makeTestTrie().Select().All().Skip(3).Reverse()
+	testResults(t, q, []testEntry{
+		{"curious", 128},
+		{"bit", 1024},
+		{"are", 5},
+		{"a", 16},
+	})
+
+	q = makeTestTrie().Select().From("hear").While(startsWithH).Reverse()
+	testResults(t, q, []testEntry{
+		{"hear", 32},
+	})
+
+	q = makeTestTrie().Select().From("hear").While(startsWithH)
+	for i := 0; i < 3; i++ {
+		_, q, _ = q.Next()
+	}
+	testResults(t, q, []testEntry{})
+	testResults(t, q.Reverse(), []testEntry{
+		{"how", 9},
+		{"hello", 1},
+		{"hear", 32},
+	})
+
+	q = makeTestTrie().Select().From("m").While(startsWithH).Reverse()
+	testResults(t, q, []testEntry{
+		{"how", 9},
+		{"hello", 1},
+		{"hear", 32},
+	})
+
+	q = trie.Map(makeTestTrie().Select().From("bit"), func(k string, _ int) int {
+		return len(k)
+	}).Reverse()
+	testResults(t, q, []testEntry{
+		{"bit", 3},
+		{"are", 3},
+		{"a", 1},
+	})
+
+	q = makeTestTrie().Select().All().Skip(20).Reverse()
+	testResults(t, q, []testEntry{})
+}
+
+func TestFromAbsentKeys(t *testing.T) {
+	as := assert.New(t)
+
+	keys := []string{"a", "ab", "abc", "b", "ba", "c", "ca", "cab", "z"}
+	tr := trie.New[string, int]()
+	for i, k := range keys {
+		tr = tr.Put(k, i)
+	}
+
+	probes := []string{"", "0", "aa", "abb", "abd", "b", "bb", "bz", "cb", "y", "zz"}
+	for _, probe := range probes {
+		var asc, desc []string
+		for _, k := range keys {
+			if k >= probe {
+				asc = append(asc, k)
+			}
+		}
+		for i := len(keys) - 1; i >= 0; i-- {
+			if keys[i] <= probe {
+				desc = append(desc, keys[i])
+			}
+		}
+		as.Equal(asc, tr.Select().From(probe).Keys(), probe)
+		as.Equal(desc, tr.Select().Descending().From(probe).Keys(), probe)
+	}
+}
+
+func makeLargeTrie(size int) trie.Trie[string, int] {
+	m := map[string]int{}
+	for i := 0; i < size; i++ {
+		m[fmt.Sprintf("%d", i)] = i
+	}
+	return trie.From[int](m)
+}
+
+func TestForEachCtx(t *testing.T) {
+	as := assert.New(t)
+
+	cnt := 0
+	err := makeTestTrie().Select().All().ForEachCtx(context.Background(),
+		func(string, int) error {
+			cnt++
+			return nil
+		},
+	)
+	as.Nil(err)
+	as.Equal(len(testMap), cnt)
+
+	stop := errors.New("stop")
+	var keys []string
+	err = makeTestTrie().Select().All().ForEachCtx(context.Background(),
+		func(k string, _ int) error {
+			if k == "curious" {
+				return stop
+			}
+			keys = append(keys, k)
+			return nil
+		},
+	)
+	as.Equal(stop, err)
+	as.Equal([]string{"a", "are", "bit"}, keys)
+}
+
+func TestForEachCtxCancel(t *testing.T) {
+	as := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cnt := 0
+	err := makeLargeTrie(10000).Select().All().ForEachCtx(ctx,
+		func(string, int) error {
+			cnt++
+			if cnt == 1000 {
+				cancel()
+			}
+			return nil
+		},
+	)
+	as.Equal(context.Canceled, err)
+	as.GreaterOrEqual(cnt, 1000)
+	as.Less(cnt, 1100)
+}
+
+func TestForEachCtxCancelAtEnd(t *testing.T) {
+	as := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cnt := 0
+	err := makeTestTrie().Select().All().ForEachCtx(ctx,
+		func(k string, _ int) error {
+			cnt++
+			if k == "you" {
+				cancel()
+			}
+			return nil
+		},
+	)
+	as.Nil(err)
+	as.Equal(len(testMap), cnt)
+
+	err = makeTestTrie().Select().All().ForEachCtx(ctx,
+		func(string, int) error {
+			return nil
+		},
+	)
+	as.Equal(context.Canceled, err)
+}
+
+func TestForEachCtxInterval(t *testing.T) {
+	as := assert.New(t)
+
+	// the context is cancelled while the last Pair is visited
+	for _, size := range []int{64, 65} {
+		ctx, cancel := context.WithCancel(context.Background())
+		cnt := 0
+		err := makeLargeTrie(size).Select().All().ForEachCtx(ctx,
+			func(string, int) error {
+				if cnt++; cnt == 64 {
+					cancel()
+				}
+				return nil
+			},
+		)
+		if size == 64 {
+			as.Nil(err)
+		} else {
+			as.Equal(context.Canceled, err)
+		}
+		as.Equal(64, cnt)
+	}
+}
+
+func TestForEachCtxFiltered(t *testing.T) {
+	as := assert.New(t)
+
+	tr := makeLargeTrie(10000)
+	match, err := tr.Match("*")
+	as.Nil(err)
+	for name, source := range map[string]trie.Query[string, int]{
+		"all":     tr.Select().All(),
+		"match":   match,
+		"take":    tr.Select().All().Take(20000),
+		"skip":    tr.Select().All().Skip(1),
+		"context": tr.Select().All().WithContext(context.Background()),
+		"mapped": trie.Map(tr.Select().All(), func(_ string, v int) int {
+			return v
+		}),
+	} {
+		// no Pair is ever visited, so the filter cancels the context
+		ctx, cancel := context.WithCancel(context.Background())
+		scanned := 0
+		err := source.Where(func(string, int) bool {
+			if scanned++; scanned == 1000 {
+				cancel()
+			}
+			return false
+		}).ForEachCtx(ctx, func(string, int) error {
+			return nil
+		})
+		as.Equal(context.Canceled, err, name)
+		as.Less(scanned, 1100, name)
+	}
+
+	none := func(string, int) bool {
+		return false
+	}
+	as.Nil(tr.Select().All().Where(none).ForEachCtx(context.Background(),
+		func(string, int) error {
+			return nil
+		},
+	))
+}
+
+func TestWithContext(t *testing.T) {
+	as := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	q := makeLargeTrie(1000).Select().All().WithContext(ctx)
+	as.Equal(1000, q.Count())
+
+	cancel()
+	as.Equal(0, q.Count())
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	q = makeTestTrie().Select().From("how").WithContext(ctx).Reverse()
+	testResults(t, q, []testEntry{
+		{"how", 9},
+		{"hello", 1},
+		{"hear", 32},
+		{"curious", 128},
+		{"bit", 1024},
+		{"are", 5},
+		{"a", 16},
+	})
+}
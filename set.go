@@ -0,0 +1,209 @@
+package trie
+
+import (
+	"iter"
+
+	"github.com/caravan/go-immutable-trie/key"
+)
+
+type (
+	// Set is an immutable, ordered set of Keys. It's backed by a Trie
+	// whose Values occupy no space
+	Set[Key key.Keyable] interface {
+		set() *set[Key] // marker
+		Contains(Key) bool
+		Count() int
+		IsEmpty() bool
+		Add(Key) Set[Key]
+		Remove(Key) Set[Key]
+		Union(Set[Key]) Set[Key]
+		Intersect(Set[Key]) Set[Key]
+		Difference(Set[Key]) Set[Key]
+		IsSubset(Set[Key]) bool
+		WithPrefix(Key) Set[Key]
+		All() iter.Seq[Key]
+		Descending() iter.Seq[Key]
+		From(Key) iter.Seq[Key]
+	}
+
+	set[Key key.Keyable] struct {
+		trie Trie[Key, struct{}]
+	}
+)
+
+// NewSet returns a new empty Set, configured by the provided Options. A
+// Set is never completed, so a WithScore Option is ignored
+func NewSet[Key key.Keyable](opts ...Option) Set[Key] {
+	return &set[Key]{
+		trie: New[Key, struct{}](withoutScore(opts)...),
+	}
+}
+
+// SetOf returns a Set containing the provided Keys, configured by the
+// provided Options as NewSet is
+func SetOf[Key key.Keyable](keys []Key, opts ...Option) Set[Key] {
+	res := New[Key, struct{}](withoutScore(opts)...)
+	for _, k := range keys {
+		res = res.Put(k, struct{}{})
+	}
+	return &set[Key]{trie: res}
+}
+
+func (s *set[Key]) set() *set[Key] {
+	return s
+}
+
+func (s *set[Key]) Contains(k Key) bool {
+	_, ok := s.trie.Get(k)
+	return ok
+}
+
+func (s *set[Key]) Count() int {
+	return s.trie.Count()
+}
+
+func (s *set[Key]) IsEmpty() bool {
+	return s.trie.IsEmpty()
+}
+
+func (s *set[Key]) Add(k Key) Set[Key] {
+	if s.Contains(k) {
+		return s
+	}
+	return &set[Key]{trie: s.trie.Put(k, struct{}{})}
+}
+
+func (s *set[Key]) Remove(k Key) Set[Key] {
+	if _, res, ok := s.trie.Remove(k); ok {
+		return &set[Key]{trie: res}
+	}
+	return s
+}
+
+// Union returns a Set containing the Keys of both Sets. If their Key
+// ranges don't overlap, the two are joined without re-inserting Keys
+func (s *set[Key]) Union(other Set[Key]) Set[Key] {
+	o := other.set()
+	switch {
+	case o.IsEmpty():
+		return s
+	case s.IsEmpty():
+		return o
+	case key.LessThan[Key](s.lastKey(), o.firstKey()):
+		return &set[Key]{trie: Join(s.trie, o.trie)}
+	case key.LessThan[Key](o.lastKey(), s.firstKey()):
+		return &set[Key]{trie: Join(o.trie, s.trie)}
+	}
+	res, from := s, o
+	if o.Count() > s.Count() {
+		res, from = o, s
+	}
+	t := res.trie
+	from.trie.Select().All().ForEach(func(k Key, _ struct{}) {
+		t = t.Put(k, struct{}{})
+	})
+	return &set[Key]{trie: t}
+}
+
+// Intersect returns a Set containing the Keys found in both Sets. Subtrees
+// of the smaller Set whose Keys are all retained are shared with it
+func (s *set[Key]) Intersect(other Set[Key]) Set[Key] {
+	small, large := s, other.set()
+	if large.Count() < small.Count() {
+		small, large = large, small
+	}
+	return small.filter(func(k Key) bool {
+		return large.Contains(k)
+	})
+}
+
+// Difference returns a Set containing the Keys of this Set that aren't
+// found in the other
+func (s *set[Key]) Difference(other Set[Key]) Set[Key] {
+	o := other.set()
+	if o.Count() < s.Count() {
+		t := s.trie
+		o.trie.Select().All().ForEach(func(k Key, _ struct{}) {
+			_, t, _ = t.Remove(k)
+		})
+		return &set[Key]{trie: t}
+	}
+	return s.filter(func(k Key) bool {
+		return !o.Contains(k)
+	})
+}
+
+// IsSubset returns whether every Key of this Set is found in the other
+func (s *set[Key]) IsSubset(other Set[Key]) bool {
+	if s.Count() > other.Count() {
+		return false
+	}
+	return s.trie.Select().All().Every(func(k Key, _ struct{}) bool {
+		return other.Contains(k)
+	})
+}
+
+// WithPrefix returns the Set of Keys that start with the provided prefix.
+// The range is cut out of this Set rather than filtered from it
+func (s *set[Key]) WithPrefix(prefix Key) Set[Key] {
+	_, res := s.trie.SplitAt(prefix)
+	if upper, ok := prefixLimit(prefix); ok {
+		res, _ = res.SplitAt(upper)
+	}
+	return &set[Key]{trie: res}
+}
+
+func (s *set[Key]) All() iter.Seq[Key] {
+	return s.keys(s.trie.Select().All())
+}
+
+func (s *set[Key]) Descending() iter.Seq[Key] {
+	return s.keys(s.trie.Select().Descending().All())
+}
+
+func (s *set[Key]) From(k Key) iter.Seq[Key] {
+	return s.keys(s.trie.Select().From(k))
+}
+
+func (s *set[Key]) keys(q Query[Key, struct{}]) iter.Seq[Key] {
+	return func(yield func(Key) bool) {
+		for p, rest, ok := q.Next(); ok; p, rest, ok = rest.Next() {
+			if !yield(p.Key()) {
+				return
+			}
+		}
+	}
+}
+
+func (s *set[Key]) filter(f func(Key) bool) Set[Key] {
+	return &set[Key]{
+		trie: FilterTrie(s.trie, func(k Key, _ struct{}) bool {
+			return f(k)
+		}),
+	}
+}
+
+func (s *set[Key]) firstKey() Key {
+	return s.trie.First().Key()
+}
+
+func (s *set[Key]) lastKey() Key {
+	return s.trie.Select().Descending().All().First().Key()
+}
+
+// prefixLimit returns the least Key that is greater than every Key
+// starting with the provided prefix. There is no such Key if the prefix
+// is empty or consists only of 0xFF bytes
+func prefixLimit[Key key.Keyable](prefix Key) (Key, bool) {
+	b := []byte(prefix)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] != 0xFF {
+			res := make([]byte, i+1)
+			copy(res, b)
+			res[i]++
+			return Key(res), true
+		}
+	}
+	var zero Key
+	return zero, false
+}
@@ -0,0 +1,158 @@
+package trie_test
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/caravan/go-immutable-trie/nibble"
+	"github.com/stretchr/testify/assert"
+)
+
+func setKeys(s trie.Set[string]) []string {
+	return slices.Collect(s.All())
+}
+
+func TestSet(t *testing.T) {
+	as := assert.New(t)
+
+	e := trie.NewSet[string]()
+	as.True(e.IsEmpty())
+	as.Equal(0, e.Count())
+	as.False(e.Contains("a"))
+
+	s := e.Add("b").Add("a").Add("c").Add("a")
+	as.Equal(3, s.Count())
+	as.True(s.Contains("a"))
+	as.True(e.IsEmpty())
+	as.Same(s, s.Add("b"))
+
+	as.Equal([]string{"a", "b", "c"}, setKeys(s))
+	as.Equal([]string{"c", "b", "a"}, slices.Collect(s.Descending()))
+	as.Equal([]string{"b", "c"}, slices.Collect(s.From("aa")))
+
+	r := s.Remove("b")
+	as.Equal([]string{"a", "c"}, setKeys(r))
+	as.Same(r, r.Remove("b"))
+	as.Equal(3, s.Count())
+
+	for range s.All() {
+		break
+	}
+}
+
+func TestSetAlgebra(t *testing.T) {
+	as := assert.New(t)
+
+	s1 := trie.SetOf([]string{"a", "b", "c", "d"})
+	s2 := trie.SetOf([]string{"c", "d", "e"})
+	empty := trie.NewSet[string]()
+
+	as.Equal([]string{"a", "b", "c", "d", "e"}, setKeys(s1.Union(s2)))
+	as.Equal([]string{"a", "b", "c", "d", "e"}, setKeys(s2.Union(s1)))
+	as.Equal([]string{"c", "d"}, setKeys(s1.Intersect(s2)))
+	as.Equal([]string{"c", "d"}, setKeys(s2.Intersect(s1)))
+	as.Equal([]string{"a", "b"}, setKeys(s1.Difference(s2)))
+	as.Equal([]string{"e"}, setKeys(s2.Difference(s1)))
+
+	as.Same(s1, s1.Union(empty))
+	as.Same(s1, empty.Union(s1))
+	as.True(s1.Intersect(empty).IsEmpty())
+	as.Equal(setKeys(s1), setKeys(s1.Difference(empty)))
+
+	as.True(trie.SetOf([]string{"b", "c"}).IsSubset(s1))
+	as.True(empty.IsSubset(s1))
+	as.True(s1.IsSubset(s1))
+	as.False(s2.IsSubset(s1))
+	as.False(s1.IsSubset(trie.SetOf([]string{"a"})))
+
+	// disjoint ranges are joined
+	lo, hi := trie.SetOf([]string{"a", "ab"}), trie.SetOf([]string{"b", "c"})
+	as.Equal([]string{"a", "ab", "b", "c"}, setKeys(lo.Union(hi)))
+	as.Equal([]string{"a", "ab", "b", "c"}, setKeys(hi.Union(lo)))
+}
+
+func TestSetMixedOptions(t *testing.T) {
+	as := assert.New(t)
+
+	lo := trie.SetOf([]string{"a", "ab", "abc"}, trie.WithStrategy(nibble.Bits1))
+	hi := trie.SetOf([]string{"b", "bc", "bcd"}, trie.WithPathCompression())
+	mid := trie.SetOf([]string{"ab", "b", "z"}, trie.WithStrategy(nibble.Bits8))
+
+	all := []string{"a", "ab", "abc", "b", "bc", "bcd"}
+	as.Equal(all, setKeys(lo.Union(hi)))
+	as.Equal(all, setKeys(hi.Union(lo)))
+	for _, res := range []trie.Set[string]{lo.Union(hi), hi.Union(lo)} {
+		for _, k := range all {
+			as.True(res.Contains(k), k)
+		}
+		as.False(res.Contains("abcd"))
+	}
+
+	as.Equal([]string{"a", "ab", "abc", "b", "bc", "bcd", "z"},
+		setKeys(lo.Union(hi).Union(mid)))
+	as.Equal([]string{"ab", "b"}, setKeys(mid.Intersect(lo.Union(hi))))
+	as.Equal([]string{"z"}, setKeys(mid.Difference(lo.Union(hi))))
+}
+
+func TestSetAlgebraRandom(t *testing.T) {
+	as := assert.New(t)
+	rng := rand.New(rand.NewSource(45))
+
+	random := func() (trie.Set[string], map[string]bool) {
+		s := trie.NewSet[string]()
+		m := map[string]bool{}
+		for i := rng.Intn(200); i > 0; i-- {
+			k := randomKey(rng)
+			s = s.Add(k)
+			m[k] = true
+		}
+		return s, m
+	}
+	expect := func(m map[string]bool) []string {
+		res := []string{}
+		for k := range m {
+			res = append(res, k)
+		}
+		sort.Strings(res)
+		return res
+	}
+
+	for i := 0; i < 50; i++ {
+		s1, m1 := random()
+		s2, m2 := random()
+		union, inter, diff := map[string]bool{}, map[string]bool{}, map[string]bool{}
+		for k := range m1 {
+			union[k] = true
+			if m2[k] {
+				inter[k] = true
+			} else {
+				diff[k] = true
+			}
+		}
+		for k := range m2 {
+			union[k] = true
+		}
+		assertKeys(as, expect(union), setKeys(s1.Union(s2)), "union")
+		assertKeys(as, expect(inter), setKeys(s1.Intersect(s2)), "intersect")
+		assertKeys(as, expect(diff), setKeys(s1.Difference(s2)), "difference")
+		as.Equal(len(diff) == 0, s1.IsSubset(s2))
+	}
+}
+
+func TestSetWithPrefix(t *testing.T) {
+	as := assert.New(t)
+
+	s := trie.SetOf([]string{"a", "ab", "abc", "abd", "ac", "b", "\xff", "\xff\xff", ""})
+	as.Equal([]string{"ab", "abc", "abd"}, setKeys(s.WithPrefix("ab")))
+	as.Equal([]string{"a", "ab", "abc", "abd", "ac"}, setKeys(s.WithPrefix("a")))
+	as.Equal([]string{"\xff", "\xff\xff"}, setKeys(s.WithPrefix("\xff")))
+	as.Equal(s.Count(), s.WithPrefix("").Count())
+	as.True(s.WithPrefix("z").IsEmpty())
+	as.Equal(9, s.Count())
+
+	b := trie.SetOf([][]byte{[]byte("x1"), []byte("x2"), []byte("y")})
+	as.Equal(2, b.WithPrefix([]byte("x")).Count())
+}
@@ -0,0 +1,168 @@
+package trie
+
+import (
+	"github.com/caravan/go-immutable-trie/key"
+	"github.com/caravan/go-immutable-trie/nibble"
+)
+
+// sizes memoizes the Count of each subtree visited while sharding
+type sizes[Key key.Keyable, Value any] map[*trie[Key, Value]]int
+
+// Join concatenates two Tries whose Key ranges don't overlap, where every
+// Key of lo is less than every Key of hi. The two Tries are merged along
+// their shared boundary rather than by re-inserting Pairs. If the ranges
+// do overlap, or the Tries weren't built with equivalent Options, the
+// Pairs of hi are inserted into lo instead
+func Join[Key key.Keyable, Value any](lo, hi Trie[Key, Value]) Trie[Key, Value] {
+	l, ok := lo.(*trie[Key, Value])
+	if !ok {
+		return hi
+	}
+	h, ok := hi.(*trie[Key, Value])
+	if !ok {
+		return lo
+	}
+	if l.cfg.equal(h.cfg) && key.LessThan[Key](l.last().key, h.key) {
+		return l.join(h, 0)
+	}
+	res := lo
+	h.forEach(func(k Key, v Value) {
+		res = res.Put(k, v)
+	})
+	return res
+}
+
+func (t *trie[Key, Value]) SplitAt(k Key) (Trie[Key, Value], Trie[Key, Value]) {
+	lo, hi := t.splitAt(k, t.nibbles(k))
+	return t.wrap(lo), t.wrap(hi)
+}
+
+func (t *trie[Key, Value]) splitAt(
+	k Key, n nibble.Nibbles[Key],
+) (*trie[Key, Value], *trie[Key, Value]) {
+	if !key.LessThan[Key](t.key, k) {
+		return nil, t
+	}
+	if t.buckets == nil || t.diverges(n) {
+		return t, nil
+	}
+	idx, n, ok := t.skipped(n).Consume()
+	if !ok {
+		panic("programmer error: split past a non-consumable key")
+	}
+	lo := make([]*trie[Key, Value], t.width())
+	hi := make([]*trie[Key, Value], t.width())
+	split := false
+	for i, bucket := range t.buckets.all() {
+		switch {
+		case i < idx:
+			lo[i] = bucket
+		case i > idx:
+			hi[i] = bucket
+			split = true
+		default:
+			lo[i], hi[i] = bucket.splitAt(k, n)
+			split = split || hi[i] != nil
+		}
+	}
+	if !split {
+		return t, nil
+	}
+	res := *t
+	res.buckets = makeBuckets(lo, t.skip())
+	rest := &trie[Key, Value]{
+		pair:    t.pair,
+		buckets: makeBuckets(hi, t.skip()),
+		cfg:     t.cfg,
+	}
+	return &res, rest.promote()
+}
+
+func (t *trie[Key, Value]) Shard(count int) []Trie[Key, Value] {
+	if count < 1 {
+		count = 1
+	}
+	s := sizes[Key, Value]{}
+	total := s.size(t)
+	res := make([]Trie[Key, Value], 0, count)
+	var rest Trie[Key, Value] = t
+	for i := 1; i < count; i++ {
+		lo, hi := rest.SplitAt(s.nth(t, i*total/count))
+		res = append(res, lo)
+		rest = hi
+	}
+	return append(res, rest)
+}
+
+func (s sizes[Key, Value]) size(t *trie[Key, Value]) int {
+	if res, ok := s[t]; ok {
+		return res
+	}
+	res := 1
+	for _, bucket := range t.buckets.nodes() {
+		res += s.size(bucket)
+	}
+	s[t] = res
+	return res
+}
+
+// nth returns the Key at the provided rank within a subtree, skipping
+// entire buckets using their memoized sizes
+func (s sizes[Key, Value]) nth(t *trie[Key, Value], rank int) Key {
+	if rank == 0 {
+		return t.key
+	}
+	rank--
+	for _, bucket := range t.buckets.nodes() {
+		if size := s.size(bucket); rank >= size {
+			rank -= size
+			continue
+		}
+		return s.nth(bucket, rank)
+	}
+	panic("programmer error: rank exceeds subtree size")
+}
+
+func (t *trie[Key, Value]) join(
+	hi *trie[Key, Value], depth int,
+) *trie[Key, Value] {
+	n := t.nibblesAt(hi.key, depth)
+	res, hi := t.expand(n), hi.expand(n)
+	for idx, bucket := range hi.buckets.all() {
+		if existing := res.buckets.get(idx); existing != nil {
+			bucket = existing.join(bucket, depth+1)
+		}
+		res = res.withBucket(idx, bucket)
+	}
+	return res.put(&hi.pair, n)
+}
+
+func (t *trie[Key, Value]) last() *trie[Key, Value] {
+	if _, bucket := t.buckets.prev(nibble.MaxSize); bucket != nil {
+		return bucket.last()
+	}
+	return t
+}
+
+func (e empty[Key, Value]) SplitAt(Key) (Trie[Key, Value], Trie[Key, Value]) {
+	return e, e
+}
+
+func (e empty[Key, Value]) Shard(count int) []Trie[Key, Value] {
+	if count < 1 {
+		count = 1
+	}
+	res := make([]Trie[Key, Value], count)
+	for i := range res {
+		res[i] = e
+	}
+	return res
+}
+
+func (t *trie[Key, Value]) nibblesAt(k Key, depth int) nibble.Nibbles[Key] {
+	n := t.nibbles(k)
+	for i := 0; i < depth; i++ {
+		_, n, _ = n.Consume()
+	}
+	return n
+}
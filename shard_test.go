@@ -0,0 +1,148 @@
+package trie_test
+
+import (
+	"fmt"
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/caravan/go-immutable-trie/nibble"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitAt(t *testing.T) {
+	as := assert.New(t)
+
+	tr := makeTestTrie()
+	all := tr.Select().All().Keys()
+	for _, k := range []string{"", "a", "are", "b", "hear", "heart", "to", "you", "zzz"} {
+		lo, hi := tr.SplitAt(k)
+		var expLo, expHi []string
+		for _, e := range all {
+			if e < k {
+				expLo = append(expLo, e)
+			} else {
+				expHi = append(expHi, e)
+			}
+		}
+		as.Equal(expLo, lo.Select().All().Keys(), k)
+		as.Equal(expHi, hi.Select().All().Keys(), k)
+		as.Equal(len(expLo), lo.Count())
+		as.Equal(len(expHi), hi.Count())
+		as.Equal(all, trie.Join(lo, hi).Select().All().Keys())
+	}
+
+	lo, hi := tr.SplitAt("zzz")
+	as.Same(tr, lo)
+	as.True(hi.IsEmpty())
+
+	lo, hi = tr.SplitAt("a")
+	as.True(lo.IsEmpty())
+	as.Same(tr, hi)
+
+	lo, hi = trie.New[string, int]().SplitAt("a")
+	as.True(lo.IsEmpty())
+	as.True(hi.IsEmpty())
+}
+
+func TestShard(t *testing.T) {
+	as := assert.New(t)
+
+	tr := makeLargeTrie(10000)
+	all := tr.Select().All().Keys()
+	shards := tr.Shard(7)
+	as.Equal(7, len(shards))
+
+	var keys []string
+	for _, s := range shards {
+		as.InDelta(10000/7, s.Count(), 1)
+		keys = append(keys, s.Select().All().Keys()...)
+	}
+	as.Equal(all, keys)
+
+	res := shards[0]
+	for _, s := range shards[1:] {
+		res = trie.Join(res, s)
+	}
+	as.Equal(all, res.Select().All().Keys())
+
+	small := makeTestTrie().Shard(20)
+	as.Equal(20, len(small))
+	cnt := 0
+	for _, s := range small {
+		cnt += s.Count()
+	}
+	as.Equal(len(testMap), cnt)
+
+	as.Equal(1, len(makeTestTrie().Shard(0)))
+	as.Equal(3, len(trie.New[string, int]().Shard(3)))
+}
+
+func TestJoinOverlapping(t *testing.T) {
+	as := assert.New(t)
+
+	lo := trie.From(map[string]int{"a": 1, "m": 2, "z": 3})
+	hi := trie.From(map[string]int{"b": 4, "m": 5, "n": 6})
+	res := trie.Join(lo, hi)
+	as.Equal([]string{"a", "b", "m", "n", "z"}, res.Select().All().Keys())
+
+	v, ok := res.Get("m")
+	as.True(ok)
+	as.Equal(5, v)
+
+	e := trie.New[string, int]()
+	as.Same(lo, trie.Join(lo, e))
+	as.Same(hi, trie.Join(e, hi))
+}
+
+func TestJoinMixedOptions(t *testing.T) {
+	as := assert.New(t)
+
+	lo := trie.New[string, int](trie.WithStrategy(nibble.Bits1)).
+		Put("a", 1).Put("ab", 2)
+	hi := trie.New[string, int]().Put("b", 3).Put("bc", 4).Put("bd", 5)
+	for _, res := range []trie.Trie[string, int]{
+		trie.Join(lo, hi),
+		trie.Join(lo.Select().All().Collect(), trie.New[string, int](
+			trie.WithStrategy(nibble.Bits8),
+		).Put("b", 3).Put("bc", 4).Put("bd", 5)),
+	} {
+		as.Nil(res.Validate())
+		as.Equal([]string{"a", "ab", "b", "bc", "bd"},
+			res.Select().All().Keys())
+		v, ok := res.Get("bc")
+		as.True(ok)
+		as.Equal(4, v)
+	}
+
+	compressed := trie.New[string, int](trie.WithPathCompression()).
+		Put("x", 6).Put("xyz", 7)
+	res := trie.Join(hi, compressed)
+	as.Nil(res.Validate())
+	as.Equal(5, res.Count())
+}
+
+func TestJoinEquivalentOptions(t *testing.T) {
+	as := assert.New(t)
+
+	for name, opts := range layouts {
+		lo := trie.New[string, int](opts...)
+		hi := trie.New[string, int](opts...)
+		for i := 0; i < 500; i++ {
+			lo = lo.Put(fmt.Sprintf("a%03d", i), i)
+			hi = hi.Put(fmt.Sprintf("b%03d", i), i)
+		}
+		res := trie.Join(lo, hi)
+		as.Nil(res.Validate(), name)
+		as.Equal(1000, res.Count(), name)
+
+		// hi's subtrees are adopted rather than rebuilt
+		as.Greater(res.SharedWith(hi).Nodes, 400, name)
+	}
+
+	byValue := func(_ string, v int) float64 { return float64(v) }
+	lo := trie.New[string, int](trie.WithScore(byValue)).Put("a", 1)
+	hi := trie.New[string, int](trie.WithScore(byValue)).Put("b", 2)
+	res := trie.Join(lo, hi)
+	as.Nil(res.Validate())
+	as.Equal([]string{"a", "b"}, res.Select().All().Keys())
+}
@@ -0,0 +1,182 @@
+package trie
+
+import (
+	"iter"
+	"unsafe"
+
+	"github.com/caravan/go-immutable-trie/key"
+)
+
+type (
+	// Inspect exposes the internal shape of a Trie
+	Inspect[Key key.Keyable, Value any] interface {
+		Root() (Node[Key, Value], bool)
+		Stats() Stats
+		SharedWith(Trie[Key, Value]) Stats
+		Validate() error
+	}
+
+	// Node exposes a single node of a Trie's internal structure, for use
+	// in debugging and analysis. Nodes that are shared structurally by
+	// different versions of a Trie compare as equal
+	Node[Key key.Keyable, Value any] interface {
+		Key() Key
+		Value() Value
+		Skip() int
+		Buckets() iter.Seq2[uint8, Node[Key, Value]]
+	}
+
+	// Stats describes the shape and approximate memory use of a Trie, or
+	// of the part of a Trie that it shares with another
+	Stats struct {
+		// Nodes is the number of nodes, each of which holds one Pair
+		Nodes int
+
+		// Branches is the number of nodes having at least one child
+		Branches int
+
+		// Occupancy counts nodes by their number of occupied bucket
+		// slots, so that Occupancy[n] is the number of nodes having n
+		// children. Its length is one more than the bucket width
+		Occupancy []int
+
+		// MaxDepth is the number of nodes along the longest path from
+		// the root, and AverageDepth is the mean depth of all nodes
+		MaxDepth     int
+		AverageDepth float64
+
+		// KeyBytes is the total length of every node's Key
+		KeyBytes int
+
+		// HeapBytes estimates the memory held by the nodes, their
+		// buckets and their Keys. It doesn't include memory referenced
+		// by Values
+		HeapBytes int
+	}
+
+	// statsBuilder accumulates Stats along with the sum of node depths
+	statsBuilder struct {
+		Stats
+		depths int
+	}
+)
+
+func (t *trie[Key, Value]) Root() (Node[Key, Value], bool) {
+	return t, true
+}
+
+// Skip returns the number of key units that this node skips before its
+// children branch, which is only ever non-zero with path compression
+func (t *trie[_, _]) Skip() int {
+	return t.skip()
+}
+
+// Buckets iterates over the occupied buckets of this node, in order
+func (t *trie[Key, Value]) Buckets() iter.Seq2[uint8, Node[Key, Value]] {
+	return func(yield func(uint8, Node[Key, Value]) bool) {
+		for idx, bucket := range t.buckets.all() {
+			if !yield(idx, bucket) {
+				return
+			}
+		}
+	}
+}
+
+func (t *trie[Key, Value]) Stats() Stats {
+	res := t.makeStatsBuilder()
+	t.addStats(res, 1)
+	return res.build()
+}
+
+// SharedWith returns the Stats of the nodes in this Trie that are also
+// reachable from the provided Trie, meaning that the two versions share
+// them structurally. Depths are measured from the root of this Trie
+func (t *trie[Key, Value]) SharedWith(other Trie[Key, Value]) Stats {
+	res := t.makeStatsBuilder()
+	if o, ok := other.(*trie[Key, Value]); ok {
+		seen := map[*trie[Key, Value]]bool{}
+		o.markNodes(seen)
+		t.addSharedStats(res, seen, 1)
+	}
+	return res.build()
+}
+
+func (t *trie[Key, Value]) makeStatsBuilder() *statsBuilder {
+	return &statsBuilder{
+		Stats: Stats{
+			Occupancy: make([]int, t.width()+1),
+		},
+	}
+}
+
+func (t *trie[Key, Value]) markNodes(seen map[*trie[Key, Value]]bool) {
+	if seen[t] {
+		return
+	}
+	seen[t] = true
+	for _, bucket := range t.buckets.nodes() {
+		bucket.markNodes(seen)
+	}
+}
+
+func (t *trie[Key, Value]) addStats(s *statsBuilder, depth int) {
+	children := t.buckets.nodes()
+	s.Nodes++
+	s.depths += depth
+	s.MaxDepth = max(s.MaxDepth, depth)
+	s.Occupancy[len(children)]++
+	if len(children) > 0 {
+		s.Branches++
+	}
+	s.KeyBytes += len(t.key)
+	s.HeapBytes += t.sizeOf()
+	for _, bucket := range children {
+		bucket.addStats(s, depth+1)
+	}
+}
+
+func (t *trie[Key, Value]) addSharedStats(
+	s *statsBuilder, seen map[*trie[Key, Value]]bool, depth int,
+) {
+	if seen[t] {
+		t.addStats(s, depth)
+		return
+	}
+	for _, bucket := range t.buckets.nodes() {
+		bucket.addSharedStats(s, seen, depth+1)
+	}
+}
+
+func (t *trie[Key, Value]) sizeOf() int {
+	res := int(unsafe.Sizeof(*t)) + len(t.key)
+	if b := t.buckets; b != nil {
+		res += int(unsafe.Sizeof(*b))
+		res += cap(b.children) * int(unsafe.Sizeof(t))
+		if b.extra != nil {
+			res += int(unsafe.Sizeof(*b.extra))
+			res += cap(b.extra.bests) * int(unsafe.Sizeof(b.extra.best))
+		}
+	}
+	return res
+}
+
+func (s *statsBuilder) build() Stats {
+	if s.Nodes > 0 {
+		s.AverageDepth = float64(s.depths) / float64(s.Nodes)
+	}
+	return s.Stats
+}
+
+func (empty[Key, Value]) Root() (Node[Key, Value], bool) {
+	return nil, false
+}
+
+func (e empty[Key, Value]) Stats() Stats {
+	return Stats{
+		Occupancy: make([]int, e.cfg.strategy().Size()+1),
+	}
+}
+
+func (e empty[Key, Value]) SharedWith(Trie[Key, Value]) Stats {
+	return e.Stats()
+}
@@ -0,0 +1,98 @@
+package trie_test
+
+import (
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/caravan/go-immutable-trie/nibble"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	as := assert.New(t)
+
+	tr := makeTestTrie()
+	s := tr.Stats()
+	as.Equal(len(testMap), s.Nodes)
+	as.Len(s.Occupancy, nibble.Size+1)
+
+	keyBytes := 0
+	for k := range testMap {
+		keyBytes += len(k)
+	}
+	as.Equal(keyBytes, s.KeyBytes)
+	as.Greater(s.HeapBytes, keyBytes)
+
+	nodes := 0
+	for _, count := range s.Occupancy {
+		nodes += count
+	}
+	as.Equal(s.Nodes, nodes)
+	as.Equal(s.Nodes-s.Occupancy[0], s.Branches)
+	as.Equal(s.Nodes-1, slots(s))
+
+	as.GreaterOrEqual(s.MaxDepth, 2)
+	as.GreaterOrEqual(s.AverageDepth, 1.0)
+	as.LessOrEqual(s.AverageDepth, float64(s.MaxDepth))
+
+	e := trie.New[string, int](trie.WithStrategy(nibble.Bits2)).Stats()
+	as.Equal(0, e.Nodes)
+	as.Equal(0.0, e.AverageDepth)
+	as.Len(e.Occupancy, 5)
+}
+
+func TestSharedWith(t *testing.T) {
+	as := assert.New(t)
+
+	t1 := makeLargeTrie(1000)
+	as.Equal(t1.Stats(), t1.SharedWith(t1))
+
+	t2 := t1.Put("zzzzzz", 0)
+	shared := t2.SharedWith(t1)
+	as.Greater(shared.Nodes, 900)
+	as.Less(shared.Nodes, t1.Count())
+	as.Less(shared.HeapBytes, t2.Stats().HeapBytes)
+	as.Equal(shared.Nodes, t1.SharedWith(t2).Nodes)
+	as.Equal(shared.KeyBytes, t1.SharedWith(t2).KeyBytes)
+
+	t3 := makeLargeTrie(1000)
+	as.Equal(0, t3.SharedWith(t1).Nodes)
+	as.Equal(0, t1.SharedWith(trie.New[string, int]()).Nodes)
+}
+
+func TestRoot(t *testing.T) {
+	as := assert.New(t)
+
+	_, ok := trie.New[string, int]().Root()
+	as.False(ok)
+
+	tr := makeTestTrie()
+	root, ok := tr.Root()
+	as.True(ok)
+	as.Equal("a", root.Key())
+	as.Equal(16, root.Value())
+	as.Equal(0, root.Skip())
+
+	var count func(trie.Node[string, int]) int
+	count = func(n trie.Node[string, int]) int {
+		res := 1
+		last := -1
+		for idx, child := range n.Buckets() {
+			as.Greater(int(idx), last)
+			last = int(idx)
+			res += count(child)
+		}
+		return res
+	}
+	as.Equal(tr.Count(), count(root))
+
+	r2, _ := tr.Put("zzz", 1).Root()
+	for idx, child := range r2.Buckets() {
+		for i, orig := range root.Buckets() {
+			if i == idx {
+				// only the bucket leading to "zzz" is copied
+				as.Equal(idx != 7, orig == child)
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package trie_test
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/caravan/go-immutable-trie/nibble"
+	"github.com/stretchr/testify/assert"
+)
+
+var strategies = []nibble.Strategy{
+	nibble.Bits1, nibble.Bits2, nibble.Bits4, nibble.Bits8,
+}
+
+func TestStrategies(t *testing.T) {
+	for _, s := range strategies {
+		t.Run(fmt.Sprintf("%d-bit", s.Bits()), func(t *testing.T) {
+			as := assert.New(t)
+
+			var keys []string
+			tr := trie.New[string, int](trie.WithStrategy(s))
+			for i := 0; i < 2000; i++ {
+				k := fmt.Sprintf("%x", i*7919)
+				keys = append(keys, k)
+				tr = tr.Put(k, i)
+			}
+			sort.Strings(keys)
+			as.Equal(len(keys), tr.Count())
+			as.Equal(keys, tr.Select().All().Keys())
+
+			v, ok := tr.Get(fmt.Sprintf("%x", 100*7919))
+			as.True(ok)
+			as.Equal(100, v)
+
+			probe := keys[500] + "!"
+			as.Equal(keys[501], tr.Select().From(probe).First().Key())
+			as.Equal(keys[500],
+				tr.Select().Descending().From(probe).First().Key())
+
+			p, ok := tr.LongestPrefix(probe)
+			as.True(ok)
+			as.Equal(keys[500], p.Key())
+
+			lo, hi := tr.SplitAt("8")
+			as.Equal(keys, append(lo.Select().All().Keys(),
+				hi.Select().All().Keys()...))
+
+			r, ok := tr.RemovePrefix("1")
+			as.True(ok)
+			r.Select().All().ForEach(func(k string, _ int) {
+				as.NotEqual(byte('1'), k[0])
+			})
+
+			var rest = tr
+			for range keys[:len(keys)-1] {
+				rest = rest.Rest()
+			}
+			as.Equal(keys[len(keys)-1], rest.First().Key())
+
+			rest = rest.Rest().Put("a", 1).Put("abc", 2).Put("ab", 3)
+			as.Equal(3, rest.Count())
+			as.Equal([]string{"a", "ab", "abc"}, rest.Select().All().Keys())
+		})
+	}
+}
+
+func TestUnknownStrategy(t *testing.T) {
+	as := assert.New(t)
+
+	as.PanicsWithValue("programmer error: unknown Strategy", func() {
+		trie.WithStrategy(nibble.Strategy(8))
+	})
+}
+
+func TestStrategyByteKeys(t *testing.T) {
+	as := assert.New(t)
+
+	tr := trie.New[[]byte, int](trie.WithStrategy(nibble.Bits1))
+	tr = tr.Put([]byte{0xff}, 1).Put([]byte{0x00}, 2).Put([]byte{0x80}, 3)
+	tr = tr.Put([]byte{0x7f}, 4).Put([]byte{}, 5).Put([]byte{0x80, 0x01}, 6)
+	as.Equal([][]byte{
+		{}, {0x00}, {0x7f}, {0x80}, {0x80, 0x01}, {0xff},
+	}, tr.Select().All().Keys())
+
+	seq := func(yield func([]byte, int) bool) {
+		tr.Select().All().ForEach(func(k []byte, v int) {
+			yield(k, v)
+		})
+	}
+	p := trie.FromParallel(seq, 2, trie.WithStrategy(nibble.Bits2))
+	as.Equal(tr.Select().All().Keys(), p.Select().All().Keys())
+	as.Equal(tr.Select().Descending().All().Keys(),
+		p.Select().Descending().All().Keys())
+}
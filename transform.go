@@ -0,0 +1,67 @@
+package trie
+
+import "github.com/caravan/go-immutable-trie/key"
+
+// Mapper transforms a Key/Value Pair into a Value of another type
+type Mapper[Key key.Keyable, Value any, Result any] func(Key, Value) Result
+
+// MapValues returns a Trie having the same Keys and structure as the
+// provided Trie, with each Value replaced by the result of the Mapper
+func MapValues[Key key.Keyable, Value any, Result any](
+	t Trie[Key, Value], fn Mapper[Key, Value, Result],
+) Trie[Key, Result] {
+	switch t := t.(type) {
+	case *trie[Key, Value]:
+		return mapValues(t, fn)
+	case empty[Key, Value]:
+		return empty[Key, Result]{cfg: t.cfg}
+	default:
+		return empty[Key, Result]{}
+	}
+}
+
+func mapValues[Key key.Keyable, Value any, Result any](
+	t *trie[Key, Value], fn Mapper[Key, Value, Result],
+) *trie[Key, Result] {
+	res := &trie[Key, Result]{
+		pair: pair[Key, Result]{t.key, fn(t.key, t.value)},
+		cfg:  t.cfg,
+	}
+	if t.buckets != nil {
+		res.buckets = &buckets[Key, Result]{
+			children: make([]*trie[Key, Result], len(t.buckets.children)),
+			skip:     t.buckets.skip,
+		}
+		res.buckets.setOccupied(t.buckets.slots())
+		for i, bucket := range t.buckets.children {
+			res.buckets.children[i] = mapValues(bucket, fn)
+		}
+		res.buckets.annotate(nil)
+	}
+	return res
+}
+
+// FilterTrie returns a Trie containing only the Pairs of the provided Trie
+// that satisfy the Filter. Subtrees that lose no Pairs are shared with the
+// original Trie rather than copied
+func FilterTrie[Key key.Keyable, Value any](
+	t Trie[Key, Value], f Filter[Key, Value],
+) Trie[Key, Value] {
+	if n, ok := t.(*trie[Key, Value]); ok {
+		return n.wrap(n.filter(f))
+	}
+	return t
+}
+
+func (t *trie[Key, Value]) filter(f Filter[Key, Value]) *trie[Key, Value] {
+	res := t
+	for idx, bucket := range t.buckets.all() {
+		if filtered := bucket.filter(f); filtered != bucket {
+			res = res.withBucket(idx, filtered)
+		}
+	}
+	if f(t.key, t.value) {
+		return res
+	}
+	return res.promote()
+}
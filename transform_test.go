@@ -0,0 +1,94 @@
+package trie_test
+
+import (
+	"fmt"
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapValues(t *testing.T) {
+	as := assert.New(t)
+
+	t1 := makeTestTrie()
+	t2 := trie.MapValues(t1, func(k string, v int) string {
+		return fmt.Sprintf("%s=%d", k, v)
+	})
+	as.Equal(t1.Count(), t2.Count())
+
+	for k, v := range testMap {
+		res, ok := t2.Get(k)
+		as.True(ok)
+		as.Equal(fmt.Sprintf("%s=%d", k, v), res)
+	}
+
+	var keys []string
+	t1.Select().All().ForEach(func(k string, _ int) {
+		keys = append(keys, k)
+	})
+	i := 0
+	t2.Select().All().ForEach(func(k string, _ string) {
+		as.Equal(keys[i], k)
+		i++
+	})
+	as.Equal(len(keys), i)
+
+	e := trie.MapValues(trie.New[string, int](), func(string, int) bool {
+		return true
+	})
+	as.True(e.IsEmpty())
+}
+
+func TestFilterTrie(t *testing.T) {
+	as := assert.New(t)
+
+	t1 := makeTestTrie()
+	t2 := trie.FilterTrie(t1, func(k string, _ int) bool {
+		return k[0] != 'h'
+	})
+	as.Equal(len(testMap)-3, t2.Count())
+	as.Equal(len(testMap), t1.Count())
+
+	for k, v := range testMap {
+		res, ok := t2.Get(k)
+		if k[0] == 'h' {
+			as.False(ok)
+			continue
+		}
+		as.True(ok)
+		as.Equal(v, res)
+	}
+
+	testResults(t, t2.Select().All(), []testEntry{
+		{"a", 16},
+		{"are", 5},
+		{"bit", 1024},
+		{"curious", 128},
+		{"there", 2},
+		{"to", 64},
+		{"today", 4},
+		{"you", 37},
+	})
+}
+
+func TestFilterTrieSharing(t *testing.T) {
+	as := assert.New(t)
+
+	t1 := makeTestTrie()
+	t2 := trie.FilterTrie(t1, func(string, int) bool {
+		return true
+	})
+	as.Same(t1, t2)
+
+	t3 := trie.FilterTrie(t1, func(string, int) bool {
+		return false
+	})
+	as.True(t3.IsEmpty())
+
+	t4 := trie.FilterTrie(t1, func(k string, _ int) bool {
+		return k != "a"
+	})
+	as.Equal(len(testMap)-1, t4.Count())
+	as.Equal("are", t4.First().Key())
+}
@@ -1,6 +1,8 @@
 package trie
 
 import (
+	"iter"
+
 	"github.com/caravan/go-immutable-trie/key"
 	"github.com/caravan/go-immutable-trie/nibble"
 )
@@ -12,6 +14,7 @@ type (
 		Read[Key, Value]
 		Split[Key, Value]
 		Write[Key, Value]
+		Inspect[Key, Value]
 	}
 
 	Read[Key key.Keyable, Value any] interface {
@@ -19,12 +22,21 @@ type (
 		Count() int
 		IsEmpty() bool
 		Select() Direction[Key, Value]
+		LongestPrefix(Key) (Pair[Key, Value], bool)
+		AllPrefixesOf(Key) iter.Seq2[Key, Value]
+		Complete(Key, int, func(Key, Value) float64) []Pair[Key, Value]
+		Scored() bool
+		Fuzzy(Key, int) iter.Seq2[Key, Value]
+		FuzzyRunes(Key, int) iter.Seq2[Key, Value]
+		Match(Key) (Query[Key, Value], error)
 	}
 
 	Split[Key key.Keyable, Value any] interface {
 		First() Pair[Key, Value]
 		Rest() Trie[Key, Value]
 		Split() (Pair[Key, Value], Trie[Key, Value], bool)
+		SplitAt(Key) (Trie[Key, Value], Trie[Key, Value])
+		Shard(int) []Trie[Key, Value]
 	}
 
 	Write[Key key.Keyable, Value any] interface {
@@ -35,18 +47,15 @@ type (
 
 	trie[Key key.Keyable, Value any] struct {
 		pair[Key, Value]
-		*buckets[Key, Value]
+		buckets *buckets[Key, Value]
+		cfg     *config
 	}
-
-	buckets[Key key.Keyable, Value any] [nibble.Size]*trie[Key, Value]
-
-	bucketsMutator[Key key.Keyable, Value any] func(*buckets[Key, Value])
 )
 
 func (*trie[_, _]) trie() {}
 
 func (t *trie[Key, Value]) Get(k Key) (Value, bool) {
-	n := nibble.Make(k)
+	n := t.nibbles(k)
 	return t.get(k, n)
 }
 
@@ -54,9 +63,8 @@ func (t *trie[Key, Value]) get(k Key, n nibble.Nibbles[Key]) (Value, bool) {
 	if key.EqualTo[Key](t.pair.key, k) {
 		return t.pair.value, true
 	}
-	if idx, n, ok := n.Consume(); ok && t.buckets != nil {
-		bucket := t.buckets[idx]
-		if bucket != nil {
+	if idx, n, ok := t.skipped(n).Consume(); ok {
+		if bucket := t.buckets.get(idx); bucket != nil {
 			return bucket.get(k, n)
 		}
 	}
@@ -66,21 +74,28 @@ func (t *trie[Key, Value]) get(k Key, n nibble.Nibbles[Key]) (Value, bool) {
 
 func (t *trie[Key, Value]) Put(k Key, v Value) Trie[Key, Value] {
 	p := &pair[Key, Value]{k, v}
-	n := nibble.Make[Key](p.key)
+	n := t.nibbles(p.key)
 	return t.put(p, n)
 }
 
 func (t *trie[Key, Value]) put(
 	p *pair[Key, Value], n nibble.Nibbles[Key],
 ) *trie[Key, Value] {
-	switch key.Compare[Key](p.key, t.pair.key) {
-	case key.Equal:
+	cmp := key.Compare[Key](p.key, t.pair.key)
+	if cmp == key.Equal {
 		return t.replacePair(p)
-	case key.Less:
-		return t.insertPair(p, n)
-	default:
-		return t.appendPair(p, n)
 	}
+	if t.cfg.compressed() {
+		res, done := t.compress(p, n)
+		if done {
+			return res
+		}
+		t = res
+	}
+	if cmp == key.Less {
+		return t.insertPair(p, t.skipped(n))
+	}
+	return t.appendPair(p, t.skipped(n))
 }
 
 func (t *trie[Key, Value]) replacePair(p *pair[Key, Value]) *trie[Key, Value] {
@@ -93,39 +108,37 @@ func (t *trie[Key, Value]) insertPair(
 	p *pair[Key, Value], n nibble.Nibbles[Key],
 ) *trie[Key, Value] {
 	if idx, next, ok := n.Branch(t.pair.key).Consume(); ok {
-		res := t.mutateBuckets(func(buckets *buckets[Key, Value]) {
-			if bucket := buckets[idx]; bucket != nil {
-				buckets[idx] = bucket.put(&t.pair, next)
-				return
-			}
-			buckets[idx] = &trie[Key, Value]{pair: t.pair}
-		})
+		res := t.withBucket(idx, t.putBucket(idx, &t.pair, next))
 		res.pair = *p
 		return res
 	}
 	panic("programmer error: demoted a non-consumable key")
-
 }
 
 func (t *trie[Key, Value]) appendPair(
 	p *pair[Key, Value], n nibble.Nibbles[Key],
 ) *trie[Key, Value] {
 	if idx, n, ok := n.Consume(); ok {
-		return t.mutateBuckets(func(buckets *buckets[Key, Value]) {
-			if bucket := buckets[idx]; bucket != nil {
-				buckets[idx] = bucket.put(p, n)
-				return
-			}
-			buckets[idx] = &trie[Key, Value]{pair: *p}
-		})
+		return t.withBucket(idx, t.putBucket(idx, p, n))
 	}
 	panic("programmer error: appended a non-consumable key")
 }
 
+// putBucket returns the result of putting a Pair into one of this node's
+// buckets, or a new leaf if that bucket is unoccupied
+func (t *trie[Key, Value]) putBucket(
+	idx uint8, p *pair[Key, Value], n nibble.Nibbles[Key],
+) *trie[Key, Value] {
+	if bucket := t.buckets.get(idx); bucket != nil {
+		return bucket.put(p, n)
+	}
+	return t.leaf(p)
+}
+
 func (t *trie[Key, Value]) RemovePrefix(k Key) (Trie[Key, Value], bool) {
-	n := nibble.Make(k)
+	n := t.nibbles(k)
 	if res, ok := t.removePrefix(k, n); ok {
-		return res, ok
+		return t.wrap(res), ok
 	}
 	return t, false
 }
@@ -140,12 +153,10 @@ func (t *trie[Key, Value]) removePrefix(
 		}
 		return nil, true
 	}
-	if idx, n, ok := n.Consume(); ok && t.buckets != nil {
-		if bucket := t.buckets[idx]; bucket != nil {
+	if idx, n, ok := t.skipped(n).Consume(); ok {
+		if bucket := t.buckets.get(idx); bucket != nil {
 			if bucket, ok := bucket.removePrefix(k, n); ok {
-				return t.mutateBuckets(func(buckets *buckets[Key, Value]) {
-					buckets[idx] = bucket
-				}), true
+				return t.withBucket(idx, bucket), true
 			}
 		}
 	}
@@ -153,12 +164,9 @@ func (t *trie[Key, Value]) removePrefix(
 }
 
 func (t *trie[Key, Value]) Remove(k Key) (Value, Trie[Key, Value], bool) {
-	n := nibble.Make(k)
+	n := t.nibbles(k)
 	if val, rest, ok := t.remove(k, n); ok {
-		if rest != nil {
-			return val, rest, true
-		}
-		return val, empty[Key, Value]{}, true
+		return val, t.wrap(rest), true
 	}
 	var zero Value
 	return zero, t, false
@@ -170,12 +178,10 @@ func (t *trie[Key, Value]) remove(
 	if key.EqualTo[Key](t.pair.key, k) {
 		return t.pair.value, t.promote(), true
 	}
-	if idx, n, ok := n.Consume(); ok && t.buckets != nil {
-		if bucket := t.buckets[idx]; bucket != nil {
+	if idx, n, ok := t.skipped(n).Consume(); ok {
+		if bucket := t.buckets.get(idx); bucket != nil {
 			if val, rest, ok := bucket.remove(k, n); ok {
-				res := *t
-				res.buckets[idx] = rest
-				return val, &res, true
+				return val, t.withBucket(idx, rest), true
 			}
 		}
 	}
@@ -183,46 +189,34 @@ func (t *trie[Key, Value]) remove(
 	return zero, nil, false
 }
 
-func (t *trie[Key, Value]) mutateBuckets(
-	mutate bucketsMutator[Key, Value],
+// withBucket returns a copy of this node having the bucket at idx replaced
+// by the provided child, or cleared if the child is nil
+func (t *trie[Key, Value]) withBucket(
+	idx uint8, child *trie[Key, Value],
 ) *trie[Key, Value] {
 	res := *t
-	var storage buckets[Key, Value]
-	if res.buckets != nil {
-		storage = *res.buckets
-	}
-	res.buckets = &storage
-	mutate(res.buckets)
+	res.buckets = t.buckets.with(idx, child)
 	return &res
 }
 
 func (t *trie[Key, Value]) promote() *trie[Key, Value] {
 	if bucket, idx := t.leastBucket(); bucket != nil {
-		res := t.mutateBuckets(func(buckets *buckets[Key, Value]) {
-			buckets[idx] = bucket.promote()
-		})
+		res := t.withBucket(idx, bucket.promote())
 		res.pair = bucket.pair
 		return res
 	}
 	return nil
 }
 
-func (t *trie[Key, Value]) leastBucket() (*trie[Key, Value], int) {
+func (t *trie[Key, Value]) leastBucket() (*trie[Key, Value], uint8) {
 	var res *trie[Key, Value]
 	var low Key
-	idx := -1
-	first := true
-	if t.buckets != nil {
-		for i, bucket := range t.buckets {
-			if bucket == nil {
-				continue
-			}
-			if k := bucket.pair.Key(); first || key.LessThan[Key](k, low) {
-				idx = i
-				low = k
-				res = bucket
-				first = false
-			}
+	var idx uint8
+	for i, bucket := range t.buckets.all() {
+		if k := bucket.pair.Key(); res == nil || key.LessThan[Key](k, low) {
+			idx = i
+			low = k
+			res = bucket
 		}
 	}
 	return res, idx
@@ -234,28 +228,18 @@ func (t *trie[Key, Value]) First() Pair[Key, Value] {
 }
 
 func (t *trie[Key, Value]) Rest() Trie[Key, Value] {
-	if rest := t.promote(); rest != nil {
-		return rest
-	}
-	return empty[Key, Value]{}
+	return t.wrap(t.promote())
 }
 
 func (t *trie[Key, Value]) Split() (Pair[Key, Value], Trie[Key, Value], bool) {
 	first := t.pair
-	if r := t.promote(); r != nil {
-		return &first, r, true
-	}
-	return &first, empty[Key, Value]{}, true
+	return &first, t.wrap(t.promote()), true
 }
 
 func (t *trie[_, _]) Count() int {
 	res := 1
-	if t.buckets != nil {
-		for _, bucket := range t.buckets {
-			if bucket != nil {
-				res += bucket.Count()
-			}
-		}
+	for _, bucket := range t.buckets.nodes() {
+		res += bucket.Count()
 	}
 	return res
 }
@@ -267,3 +251,28 @@ func (t *trie[_, _]) IsEmpty() bool {
 func (t *trie[Key, Value]) Select() Direction[Key, Value] {
 	return makeQuery[Key, Value](t)
 }
+
+// leaf creates a new childless node that shares this node's configuration
+func (t *trie[Key, Value]) leaf(p *pair[Key, Value]) *trie[Key, Value] {
+	return &trie[Key, Value]{
+		pair: *p,
+		cfg:  t.cfg,
+	}
+}
+
+// wrap returns the provided node as a Trie, or an empty Trie sharing this
+// node's configuration if it is nil
+func (t *trie[Key, Value]) wrap(res *trie[Key, Value]) Trie[Key, Value] {
+	if res != nil {
+		return res
+	}
+	return empty[Key, Value]{cfg: t.cfg}
+}
+
+func (t *trie[Key, Value]) nibbles(k Key) nibble.Nibbles[Key] {
+	return nibble.MakeWith(t.cfg.strategy(), k)
+}
+
+func (t *trie[_, _]) width() int {
+	return t.cfg.strategy().Size()
+}
@@ -1,6 +1,8 @@
 package trie_test
 
 import (
+	"fmt"
+	"maps"
 	"testing"
 
 	trie "github.com/caravan/go-immutable-trie"
@@ -164,6 +166,21 @@ func TestSplitting(t *testing.T) {
 	as.Equal(0, len(check))
 }
 
+func TestRemoveLeavesOriginal(t *testing.T) {
+	as := assert.New(t)
+
+	t1 := makeTestTrie()
+	for k := range testMap {
+		_, t2, ok := t1.Remove(k)
+		as.True(ok)
+		as.Equal(len(testMap)-1, t2.Count())
+		as.Equal(len(testMap), t1.Count())
+		v, ok := t1.Get(k)
+		as.True(ok)
+		as.Equal(testMap[k], v)
+	}
+}
+
 func TestRemovePrefix(t *testing.T) {
 	as := assert.New(t)
 
@@ -181,3 +198,87 @@ func TestRemovePrefix(t *testing.T) {
 	as.False(ok)
 	as.Equal(t1, t4)
 }
+
+func TestFromParallel(t *testing.T) {
+	as := assert.New(t)
+
+	m := map[string]int{}
+	for i := 0; i < 20000; i++ {
+		m[fmt.Sprintf("%x", i*7919)] = i
+	}
+	m[""] = -1
+
+	for _, workers := range []int{0, 1, 4} {
+		tr := trie.FromParallel(maps.All(m), workers)
+		as.Equal(len(m), tr.Count())
+		as.Equal(trie.From(m).Select().All().Keys(), tr.Select().All().Keys())
+		for k, v := range m {
+			res, ok := tr.Get(k)
+			as.True(ok)
+			as.Equal(v, res)
+		}
+	}
+
+	tr := trie.FromParallel(maps.All(testMap), 2)
+	as.Equal(makeTestTrie().Select().All().ToMap(), tr.Select().All().ToMap())
+	as.Equal("a", tr.First().Key())
+}
+
+func TestFromParallelPartitions(t *testing.T) {
+	as := assert.New(t)
+
+	// Keys that share their leading units are still spread across workers
+	var hex, paths []string
+	for i := 0; i < 10000; i++ {
+		hex = append(hex, fmt.Sprintf("%x", i*7919))
+		paths = append(paths, fmt.Sprintf("/api/v1/tenants/%08d/config", i))
+	}
+	for _, keys := range [][]string{hex, paths} {
+		as.GreaterOrEqual(trie.Partitions(keys, 4), 16)
+	}
+	// duplicates of a single Key leave nothing to build concurrently
+	as.Equal(0, trie.Partitions([]string{"a", "a", "a"}, 4))
+	as.Equal(1, trie.Partitions(nil, 4))
+
+	for name, opts := range layouts {
+		m := map[string]int{"": -1}
+		for i, k := range paths[:2000] {
+			m[k] = i
+			m[hex[i]] = i
+		}
+		tr := trie.FromParallel(maps.All(m), 4, opts...)
+		as.Nil(tr.Validate(), name)
+		as.Equal(len(m), tr.Count(), name)
+		as.Equal(trie.From(m).Select().All().Keys(), tr.Select().All().Keys())
+		for k, v := range m {
+			res, ok := tr.Get(k)
+			as.True(ok)
+			as.Equal(v, res)
+		}
+	}
+}
+
+func TestFromParallelDuplicates(t *testing.T) {
+	as := assert.New(t)
+
+	seq := func(yield func([]byte, int) bool) {
+		for i, k := range []string{"b", "a", "b", "", "ab", ""} {
+			if !yield([]byte(k), i) {
+				return
+			}
+		}
+	}
+	tr := trie.FromParallel[[]byte, int](seq, 2)
+	as.Equal(4, tr.Count())
+
+	v, ok := tr.Get([]byte("b"))
+	as.True(ok)
+	as.Equal(2, v)
+
+	v, ok = tr.Get([]byte{})
+	as.True(ok)
+	as.Equal(5, v)
+
+	e := trie.FromParallel(maps.All(map[string]int{}), 2)
+	as.True(e.IsEmpty())
+}
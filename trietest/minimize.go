@@ -0,0 +1,48 @@
+package trietest
+
+// Minimize reduces a failing sequence of Ops to a smaller one for which
+// the predicate still reports failure. It removes progressively smaller
+// chunks of Ops, then shortens Keys one byte at a time, repeating until
+// no further reduction fails. The predicate must report failure for the
+// provided sequence
+func Minimize(ops Ops, fails func(Ops) bool) Ops {
+	for {
+		if res, ok := removeChunks(ops, fails); ok {
+			ops = res
+			continue
+		}
+		if res, ok := shortenKeys(ops, fails); ok {
+			ops = res
+			continue
+		}
+		return ops
+	}
+}
+
+func removeChunks(ops Ops, fails func(Ops) bool) (Ops, bool) {
+	for size := len(ops) / 2; size > 0; size /= 2 {
+		for start := 0; start+size <= len(ops); start += size {
+			res := make(Ops, 0, len(ops)-size)
+			res = append(res, ops[:start]...)
+			res = append(res, ops[start+size:]...)
+			if fails(res) {
+				return res, true
+			}
+		}
+	}
+	return ops, false
+}
+
+func shortenKeys(ops Ops, fails func(Ops) bool) (Ops, bool) {
+	for i, o := range ops {
+		for j := range o.Key {
+			res := make(Ops, len(ops))
+			copy(res, ops)
+			res[i].Key = o.Key[:j] + o.Key[j+1:]
+			if fails(res) {
+				return res, true
+			}
+		}
+	}
+	return ops, false
+}
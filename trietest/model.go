@@ -0,0 +1,115 @@
+// Package trietest provides model-based testing of Tries. Sequences of
+// operations are applied to both a Trie and a simple sorted reference
+// Model, and any disagreement between the two is reported. Failing
+// sequences can be minimized and printed as Go literals, so that they can
+// be kept as reproducible regression tests
+package trietest
+
+import (
+	"sort"
+	"strings"
+)
+
+type (
+	// Model is a reference implementation of a Trie's contents, kept as a
+	// slice of Entries sorted by Key
+	Model struct {
+		entries []Entry
+	}
+
+	// Entry is a Key/Value pair held by a Model
+	Entry struct {
+		Key   string
+		Value int
+	}
+)
+
+// Entries returns the Entries of the Model in ascending Key order
+func (m *Model) Entries() []Entry {
+	return m.entries
+}
+
+// Clone returns a copy of the Model that can be changed independently
+func (m *Model) Clone() *Model {
+	res := &Model{entries: make([]Entry, len(m.entries))}
+	copy(res.entries, m.entries)
+	return res
+}
+
+func (m *Model) Get(k string) (int, bool) {
+	if i, ok := m.search(k); ok {
+		return m.entries[i].Value, true
+	}
+	return 0, false
+}
+
+func (m *Model) Put(k string, v int) {
+	i, ok := m.search(k)
+	if ok {
+		m.entries[i].Value = v
+		return
+	}
+	m.entries = append(m.entries, Entry{})
+	copy(m.entries[i+1:], m.entries[i:])
+	m.entries[i] = Entry{k, v}
+}
+
+func (m *Model) Remove(k string) (int, bool) {
+	i, ok := m.search(k)
+	if !ok {
+		return 0, false
+	}
+	res := m.entries[i].Value
+	m.entries = append(m.entries[:i], m.entries[i+1:]...)
+	return res, true
+}
+
+func (m *Model) RemovePrefix(k string) bool {
+	var res []Entry
+	for _, e := range m.entries {
+		if !strings.HasPrefix(e.Key, k) {
+			res = append(res, e)
+		}
+	}
+	removed := len(res) != len(m.entries)
+	m.entries = res
+	return removed
+}
+
+// Split removes and returns the Entry having the least Key
+func (m *Model) Split() (Entry, bool) {
+	if len(m.entries) == 0 {
+		return Entry{}, false
+	}
+	res := m.entries[0]
+	m.entries = m.entries[1:]
+	return res, true
+}
+
+// From returns the Entries having Keys greater than or equal to the
+// provided Key, in ascending order
+func (m *Model) From(k string) []Entry {
+	i, _ := m.search(k)
+	return m.entries[i:]
+}
+
+// DescendingFrom returns the Entries having Keys less than or equal to
+// the provided Key, in descending order
+func (m *Model) DescendingFrom(k string) []Entry {
+	i, ok := m.search(k)
+	if ok {
+		i++
+	}
+	res := make([]Entry, 0, i)
+	for i--; i >= 0; i-- {
+		res = append(res, m.entries[i])
+	}
+	return res
+}
+
+func (m *Model) search(k string) (int, bool) {
+	i := sort.Search(len(m.entries), func(i int) bool {
+		return m.entries[i].Key >= k
+	})
+	return i, i < len(m.entries) && m.entries[i].Key == k
+}
@@ -0,0 +1,128 @@
+package trietest
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+type (
+	// Kind identifies the operation that an Op performs
+	Kind uint8
+
+	// Op is a single operation of a test sequence. Value is only used by
+	// Put
+	Op struct {
+		Kind  Kind
+		Key   string
+		Value int
+	}
+
+	// Ops is a sequence of operations
+	Ops []Op
+)
+
+// Operations that change the contents of a Trie, followed by those that
+// only query it
+const (
+	Put Kind = iota
+	Remove
+	RemovePrefix
+	Split
+	From
+	Descending
+	kindCount
+)
+
+var kindNames = [...]string{
+	Put:          "Put",
+	Remove:       "Remove",
+	RemovePrefix: "RemovePrefix",
+	Split:        "Split",
+	From:         "From",
+	Descending:   "Descending",
+}
+
+func (k Kind) String() string {
+	if k < kindCount {
+		return kindNames[k]
+	}
+	return fmt.Sprintf("Kind(%d)", k)
+}
+
+func (o Op) String() string {
+	switch o.Kind {
+	case Put:
+		return fmt.Sprintf("Put(%q, %d)", o.Key, o.Value)
+	case Split:
+		return "Split()"
+	default:
+		return fmt.Sprintf("%s(%q)", o.Kind, o.Key)
+	}
+}
+
+// GoString renders the sequence as a Go literal, suitable for pasting
+// into a regression test
+func (ops Ops) GoString() string {
+	var sb strings.Builder
+	sb.WriteString("trietest.Ops{\n")
+	for _, o := range ops {
+		fmt.Fprintf(&sb, "\t{Kind: trietest.%s, Key: %q", o.Kind, o.Key)
+		if o.Kind == Put {
+			fmt.Fprintf(&sb, ", Value: %d", o.Value)
+		}
+		sb.WriteString("},\n")
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// Decode interprets arbitrary bytes as a sequence of Ops, so that fuzzed
+// inputs can drive a test. Each Op consumes a byte selecting its Kind, a
+// byte giving its Key's length, and then the Key itself
+func Decode(data []byte) Ops {
+	var res Ops
+	for len(data) >= 2 {
+		kind := Kind(data[0] % uint8(kindCount))
+		size := min(int(data[1]%8), len(data)-2)
+		res = append(res, Op{
+			Kind:  kind,
+			Key:   string(data[2 : 2+size]),
+			Value: len(res),
+		})
+		data = data[2+size:]
+	}
+	return res
+}
+
+// Encode produces the bytes that Decode would interpret as the provided
+// sequence. Keys longer than Decode supports are truncated
+func Encode(ops Ops) []byte {
+	var res []byte
+	for _, o := range ops {
+		k := o.Key[:min(len(o.Key), 7)]
+		res = append(res, byte(o.Kind), byte(len(k)))
+		res = append(res, k...)
+	}
+	return res
+}
+
+// Generate produces a random sequence of Ops whose Keys are drawn from
+// the provided alphabet, with lengths of up to maxLen. Small alphabets
+// produce many shared prefixes and repeated Keys
+func Generate(rng *rand.Rand, count int, alphabet string, maxLen int) Ops {
+	res := make(Ops, count)
+	for i := range res {
+		var sb strings.Builder
+		for j := rng.Intn(maxLen + 1); j > 0; j-- {
+			sb.WriteByte(alphabet[rng.Intn(len(alphabet))])
+		}
+		kind := Kind(rng.Intn(int(kindCount)))
+		if rng.Intn(2) == 0 {
+			// weight towards growing the Trie
+			kind = Put
+		}
+		res[i] = Op{Kind: kind, Key: sb.String(), Value: i}
+	}
+	return res
+}
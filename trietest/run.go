@@ -0,0 +1,115 @@
+package trietest
+
+import (
+	"fmt"
+
+	trie "github.com/caravan/go-immutable-trie"
+)
+
+// Trie is the kind of Trie that the package tests
+type Trie = trie.Trie[string, int]
+
+// Run applies a sequence of Ops to both the provided Trie and a Model,
+// checking after each one that the two agree and that the version of the
+// Trie preceding the Op is unchanged. The first disagreement is returned
+func Run(t Trie, ops Ops) error {
+	m := &Model{}
+	t.Select().All().ForEach(func(k string, v int) {
+		m.entries = append(m.entries, Entry{k, v})
+	})
+	for i, o := range ops {
+		prev, prevModel := t, m.Clone()
+		var err error
+		if t, err = o.apply(t, m); err == nil {
+			err = Check(t, m)
+		}
+		if err == nil {
+			if err = Check(prev, prevModel); err != nil {
+				err = fmt.Errorf("previous version changed: %w", err)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("op %d, %s: %w", i, o, err)
+		}
+	}
+	return nil
+}
+
+func (o Op) apply(t Trie, m *Model) (Trie, error) {
+	switch o.Kind {
+	case Put:
+		m.Put(o.Key, o.Value)
+		return t.Put(o.Key, o.Value), nil
+	case Remove:
+		want, wantOK := m.Remove(o.Key)
+		got, res, ok := t.Remove(o.Key)
+		if ok != wantOK || got != want {
+			return res, fmt.Errorf(
+				"removed (%d, %t), expected (%d, %t)", got, ok, want, wantOK,
+			)
+		}
+		return res, nil
+	case RemovePrefix:
+		want := m.RemovePrefix(o.Key)
+		res, ok := t.RemovePrefix(o.Key)
+		if ok != want {
+			return res, fmt.Errorf("removed %t, expected %t", ok, want)
+		}
+		return res, nil
+	case Split:
+		want, wantOK := m.Split()
+		p, res, ok := t.Split()
+		if ok != wantOK {
+			return res, fmt.Errorf("split %t, expected %t", ok, wantOK)
+		}
+		if ok && (p.Key() != want.Key || p.Value() != want.Value) {
+			return res, fmt.Errorf("split %q, expected %q", p.Key(), want.Key)
+		}
+		return res, nil
+	case From:
+		return t, compare(t.Select().From(o.Key), m.From(o.Key))
+	case Descending:
+		q := t.Select().Descending().From(o.Key)
+		return t, compare(q, m.DescendingFrom(o.Key))
+	default:
+		return t, fmt.Errorf("unknown operation: %s", o.Kind)
+	}
+}
+
+// Check returns an error if the contents of a Trie differ from those of a
+// Model, or if the Trie fails validation
+func Check(t Trie, m *Model) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	if got, want := t.Count(), len(m.entries); got != want {
+		return fmt.Errorf("count is %d, expected %d", got, want)
+	}
+	for _, e := range m.entries {
+		if v, ok := t.Get(e.Key); !ok || v != e.Value {
+			return fmt.Errorf("get %q is (%d, %t), expected %d",
+				e.Key, v, ok, e.Value,
+			)
+		}
+	}
+	return compare(t.Select().All(), m.entries)
+}
+
+func compare(q trie.Query[string, int], want []Entry) error {
+	i := 0
+	for p, rest, ok := q.Next(); ok; p, rest, ok = rest.Next() {
+		if i >= len(want) {
+			return fmt.Errorf("unexpected %q after %d entries", p.Key(), i)
+		}
+		if e := want[i]; p.Key() != e.Key || p.Value() != e.Value {
+			return fmt.Errorf("entry %d is %q=%d, expected %q=%d",
+				i, p.Key(), p.Value(), e.Key, e.Value,
+			)
+		}
+		i++
+	}
+	if i < len(want) {
+		return fmt.Errorf("missing %q after %d entries", want[i].Key, i)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package trietest_test
+
+import (
+	"math/rand"
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/caravan/go-immutable-trie/trietest"
+	"github.com/stretchr/testify/assert"
+)
+
+// forgetful claims to remove Keys that start with "x", but doesn't
+type forgetful struct {
+	trietest.Trie
+}
+
+func (f forgetful) Put(k string, v int) trietest.Trie {
+	return forgetful{f.Trie.Put(k, v)}
+}
+
+func (f forgetful) Remove(k string) (int, trietest.Trie, bool) {
+	if len(k) > 0 && k[0] == 'x' {
+		v, _ := f.Get(k)
+		return v, f, true
+	}
+	v, r, ok := f.Trie.Remove(k)
+	return v, forgetful{r}, ok
+}
+
+func (f forgetful) RemovePrefix(k string) (trietest.Trie, bool) {
+	r, ok := f.Trie.RemovePrefix(k)
+	return forgetful{r}, ok
+}
+
+func (f forgetful) Split() (trie.Pair[string, int], trietest.Trie, bool) {
+	p, r, ok := f.Trie.Split()
+	return p, forgetful{r}, ok
+}
+
+func TestModel(t *testing.T) {
+	as := assert.New(t)
+
+	m := &trietest.Model{}
+	m.Put("b", 2)
+	m.Put("a", 1)
+	m.Put("ab", 3)
+	m.Put("c", 4)
+	m.Put("b", 5)
+	as.Equal([]trietest.Entry{{"a", 1}, {"ab", 3}, {"b", 5}, {"c", 4}},
+		m.Entries(),
+	)
+
+	c := m.Clone()
+	v, ok := m.Remove("b")
+	as.True(ok)
+	as.Equal(5, v)
+	_, ok = m.Remove("b")
+	as.False(ok)
+	as.Len(c.Entries(), 4)
+
+	as.Equal([]trietest.Entry{{"c", 4}}, m.From("abc"))
+	as.Equal([]trietest.Entry{{"ab", 3}, {"a", 1}}, m.DescendingFrom("ab"))
+	as.True(m.RemovePrefix("a"))
+	as.False(m.RemovePrefix("a"))
+
+	e, ok := m.Split()
+	as.True(ok)
+	as.Equal(trietest.Entry{"c", 4}, e)
+	_, ok = m.Split()
+	as.False(ok)
+}
+
+func TestDecode(t *testing.T) {
+	as := assert.New(t)
+
+	ops := trietest.Ops{
+		{Kind: trietest.Put, Key: "abc", Value: 0},
+		{Kind: trietest.RemovePrefix, Key: "", Value: 1},
+		{Kind: trietest.Descending, Key: "\x00z", Value: 2},
+	}
+	as.Equal(ops, trietest.Decode(trietest.Encode(ops)))
+	as.Empty(trietest.Decode([]byte{3}))
+	as.Equal(trietest.Ops{{Kind: trietest.Remove, Key: "x"}},
+		trietest.Decode([]byte{7, 15, 'x'}),
+	)
+}
+
+func TestGoString(t *testing.T) {
+	as := assert.New(t)
+
+	ops := trietest.Ops{
+		{Kind: trietest.Put, Key: "a", Value: 1},
+		{Kind: trietest.Split},
+	}
+	as.Equal(""+
+		"trietest.Ops{\n"+
+		"\t{Kind: trietest.Put, Key: \"a\", Value: 1},\n"+
+		"\t{Kind: trietest.Split, Key: \"\"},\n"+
+		"}",
+		ops.GoString(),
+	)
+	as.Equal("Put(\"a\", 1)", ops[0].String())
+	as.Equal("Split()", ops[1].String())
+	as.Equal("Kind(9)", trietest.Kind(9).String())
+}
+
+func TestRun(t *testing.T) {
+	as := assert.New(t)
+
+	rng := rand.New(rand.NewSource(43))
+	for i := 0; i < 20; i++ {
+		ops := trietest.Generate(rng, 100, "abx", 4)
+		as.Nil(trietest.Run(trie.New[string, int](), ops))
+	}
+}
+
+func TestMinimize(t *testing.T) {
+	as := assert.New(t)
+
+	fails := func(ops trietest.Ops) bool {
+		return trietest.Run(forgetful{trie.New[string, int]()}, ops) != nil
+	}
+
+	rng := rand.New(rand.NewSource(43))
+	ops := trietest.Generate(rng, 200, "abx", 6)
+	as.True(fails(ops))
+
+	res := trietest.Minimize(ops, fails)
+	as.True(fails(res))
+	as.Equal(trietest.Ops{
+		{Kind: trietest.Remove, Key: "x", Value: res[0].Value},
+	}, res)
+}
@@ -0,0 +1,112 @@
+package trie
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/caravan/go-immutable-trie/key"
+	"github.com/caravan/go-immutable-trie/nibble"
+)
+
+// ErrCorrupt is wrapped by the errors that Validate returns
+var ErrCorrupt = errors.New("trie: corrupt structure")
+
+// Validate checks the internal consistency of a Trie. It verifies that
+// each node's Key is the least in its subtree, that each child sits in the
+// bucket matching its Key's unit at that position, that bucket, path
+// compression and score metadata agree with the nodes, and that no node
+// can be reached more than once. The returned error wraps ErrCorrupt
+func (t *trie[Key, Value]) Validate() error {
+	seen := map[*trie[Key, Value]]bool{}
+	return t.validate(t.cfg, nil, seen)
+}
+
+func (t *trie[Key, Value]) validate(
+	cfg *config, path []uint8, seen map[*trie[Key, Value]]bool,
+) error {
+	if seen[t] {
+		return t.corrupt("is reachable more than once")
+	}
+	seen[t] = true
+	if !t.cfg.equal(cfg) {
+		return t.corrupt("has a different configuration than its root")
+	}
+
+	units := t.units()
+	if len(units) < len(path) || string(units[:len(path)]) != string(path) {
+		return t.corrupt("is in the wrong bucket")
+	}
+	skip := t.skip()
+	if skip < 0 || len(units) < len(path)+skip {
+		return t.corrupt("skips %d units past its Key", skip)
+	}
+	branch := units[:len(path)+skip]
+
+	if err := t.validateBuckets(); err != nil {
+		return err
+	}
+	for idx, bucket := range t.buckets.all() {
+		if !key.LessThan[Key](t.key, bucket.key) {
+			return t.corrupt("isn't the least Key in its subtree")
+		}
+		child := append(branch[:len(branch):len(branch)], idx)
+		if err := bucket.validate(cfg, child, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *trie[Key, Value]) validateBuckets() error {
+	b := t.buckets
+	if b == nil {
+		return nil
+	}
+	switch {
+	case len(b.children) == 0:
+		return t.corrupt("has no children in its buckets")
+	case b.slots().count() != len(b.children):
+		return t.corrupt("has a bucket bitmap that disagrees with its children")
+	case b.slots().prev(nibble.MaxSize) >= t.width():
+		return t.corrupt("has buckets outside of its width")
+	}
+	for _, bucket := range b.children {
+		if bucket == nil {
+			return t.corrupt("has an empty bucket")
+		}
+	}
+	if score, ok := scorer[Key, Value](t.cfg); ok {
+		best := math.Inf(-1)
+		bests := b.bests()
+		for i, bucket := range b.children {
+			bucketBest := bucket.best(score)
+			if len(bests) != len(b.children) || bests[i] != bucketBest {
+				return t.corrupt("has a stale score annotation")
+			}
+			best = max(best, bucketBest)
+		}
+		if best != b.best() {
+			return t.corrupt("has a stale score annotation")
+		}
+	}
+	return nil
+}
+
+// units returns every unit of this node's Key
+func (t *trie[Key, Value]) units() []uint8 {
+	var res []uint8
+	for u, n, ok := t.nibbles(t.key).Consume(); ok; u, n, ok = n.Consume() {
+		res = append(res, u)
+	}
+	return res
+}
+
+func (t *trie[Key, Value]) corrupt(format string, args ...any) error {
+	args = append([]any{ErrCorrupt, string(t.key)}, args...)
+	return fmt.Errorf("%w: node %q "+format, args...)
+}
+
+func (empty[_, _]) Validate() error {
+	return nil
+}
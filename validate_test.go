@@ -0,0 +1,67 @@
+package trie_test
+
+import (
+	"math/rand"
+	"testing"
+
+	trie "github.com/caravan/go-immutable-trie"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	as := assert.New(t)
+
+	as.Nil(trie.New[string, int]().Validate())
+	as.Nil(makeTestTrie().Validate())
+
+	for name, opts := range layouts {
+		rng := rand.New(rand.NewSource(41))
+		tr := trie.New[string, int](opts...)
+		for i := 0; i < 2000; i++ {
+			k := randomKey(rng)
+			switch rng.Intn(4) {
+			case 0:
+				_, tr, _ = tr.Remove(k)
+			case 1:
+				tr, _ = tr.RemovePrefix(k)
+			default:
+				tr = tr.Put(k, i)
+			}
+			if err := tr.Validate(); err != nil {
+				as.Fail(name, err.Error())
+				return
+			}
+		}
+
+		lo, hi := tr.SplitAt("/api/v1/b")
+		as.Nil(lo.Validate(), name)
+		as.Nil(hi.Validate(), name)
+		as.Nil(trie.Join(lo, hi).Validate(), name)
+		as.Nil(trie.FilterTrie(tr, func(_ string, v int) bool {
+			return v%3 == 0
+		}).Validate(), name)
+	}
+}
+
+func TestValidateCorruption(t *testing.T) {
+	as := assert.New(t)
+
+	expected := map[string]string{
+		"unordered":     "isn't the least Key in its subtree",
+		"misplaced":     "is in the wrong bucket",
+		"reached twice": "is reachable more than once",
+		"bitmap":        "bitmap that disagrees with its children",
+		"empty bucket":  "has an empty bucket",
+		"skip":          "skips 3 units past its Key",
+		"config":        "has a different configuration",
+	}
+	as.Len(trie.Corruptions, len(expected))
+
+	tr := makeTestTrie()
+	for name, msg := range expected {
+		err := trie.Corrupt(tr, name).Validate()
+		as.ErrorIs(err, trie.ErrCorrupt, name)
+		as.Contains(err.Error(), msg, name)
+	}
+	as.Nil(tr.Validate())
+}